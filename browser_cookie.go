@@ -0,0 +1,199 @@
+package upload
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// chromiumKeyringPrefix prefixes the cookie values encrypted with a key
+	// stored in the desktop keyring.
+	chromiumKeyringPrefix = "v11"
+
+	// chromiumPeanutsPassword is the password Chromium derives the cookie
+	// encryption key from on Linux without a keyring, e.g. with
+	// `--password-store=basic`.
+	chromiumPeanutsPassword = "peanuts"
+
+	// chromiumPeanutsPrefix prefixes the cookie values encrypted with the
+	// peanuts key.
+	chromiumPeanutsPrefix = "v10"
+
+	chromiumSalt       = "saltysalt"
+	slackCookieDomain  = "slack.com"
+	slackSessionCookie = "d"
+)
+
+var (
+	// browserCookieDatabasePaths are the paths of the cookie databases
+	// relative to the Firefox and Chromium profile directories.
+	browserCookieDatabasePaths = []string{
+		"cookies.sqlite",
+		filepath.Join("Network", "Cookies"),
+		"Cookies",
+	}
+)
+
+// browserSlackCookie returns the Slack session cookie read from the Firefox
+// or Chromium profile directory or cookie database at the specified path, in
+// the `d=<value>` form of a Cookie header.
+func browserSlackCookie(profilePath string) (cookie string, err error) {
+	databasePath, err := browserCookieDatabasePath(profilePath)
+	if err != nil {
+		return "", err
+	}
+
+	database, err := openSQLiteDatabase(databasePath)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := firefoxSlackCookie(database)
+	if err != nil {
+		value, err = chromiumSlackCookie(database)
+	}
+	if err != nil {
+		return "", errors.WithMessagef(err, "reading Slack cookie from browser cookie database failed, path: '%+v'", databasePath)
+	} else if value == "" {
+		return "", fmt.Errorf("browser cookie database has no Slack `d` cookie, sign in to Slack in the browser first, path: '%+v'", databasePath)
+	}
+
+	return slackSessionCookie + "=" + value, nil
+}
+
+// browserCookieDatabasePath returns the cookie database path of the profile
+// directory or the specified path when it is a file.
+func browserCookieDatabasePath(profilePath string) (databasePath string, err error) {
+	info, err := os.Stat(profilePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "checking browser profile failed, path: '%+v'", profilePath)
+	} else if !info.IsDir() {
+		return profilePath, nil
+	}
+
+	for _, relativePath := range browserCookieDatabasePaths {
+		databasePath = filepath.Join(profilePath, relativePath)
+		if info, err := os.Stat(databasePath); err == nil &&
+			!info.IsDir() {
+			return databasePath, nil
+		}
+	}
+
+	return "", fmt.Errorf("browser profile has no cookie database, expected one of '%+v', path: '%+v'", browserCookieDatabasePaths, profilePath)
+}
+
+// chromiumSlackCookie returns the latest expiring Slack session cookie value
+// of a Chromium cookie database, decrypting it with the peanuts key when it
+// is encrypted.
+func chromiumSlackCookie(database *sqliteDatabase) (value string, err error) {
+	rows, err := database.tableRows("cookies")
+	if err != nil {
+		return "", err
+	}
+
+	row := latestSlackCookieRow(rows, "host_key", "expires_utc")
+	if row == nil {
+		return "", nil
+	}
+
+	value, _ = row["value"].(string)
+	if value != "" {
+		return value, nil
+	}
+
+	encryptedValue, _ := row["encrypted_value"].([]byte)
+	hostKey, _ := row["host_key"].(string)
+
+	return decryptChromiumCookie(encryptedValue, hostKey)
+}
+
+// decryptChromiumCookie returns the cookie value encrypted with the peanuts
+// key, dropping the host hash newer Chromium versions prepend to it.
+func decryptChromiumCookie(encryptedValue []byte, hostKey string) (value string, err error) {
+	if bytes.HasPrefix(encryptedValue, []byte(chromiumKeyringPrefix)) {
+		return "", fmt.Errorf("cookie is encrypted with the Chromium keyring key, which is not supported, start the browser with `--password-store=basic` and sign in again")
+	} else if !bytes.HasPrefix(encryptedValue, []byte(chromiumPeanutsPrefix)) {
+		return "", fmt.Errorf("cookie is encrypted with an unsupported Chromium scheme")
+	}
+
+	cipherText := encryptedValue[len(chromiumPeanutsPrefix):]
+	if len(cipherText) == 0 ||
+		len(cipherText)%aes.BlockSize != 0 {
+		return "", fmt.Errorf("encrypted Chromium cookie has an invalid length, length: '%+v'", len(cipherText))
+	}
+
+	// Note: PBKDF2-HMAC-SHA1 of a single iteration and a key shorter than the
+	// hash is the first HMAC block itself.
+	keyHash := hmac.New(sha1.New, []byte(chromiumPeanutsPassword))
+	_, _ = keyHash.Write(append([]byte(chromiumSalt), 0, 0, 0, 1))
+	block, err := aes.NewCipher(keyHash.Sum(nil)[:16])
+	if err != nil {
+		return "", errors.Wrap(err, "instantiating Chromium cookie cipher failed")
+	}
+
+	plainText := make([]byte, len(cipherText))
+	cipher.NewCBCDecrypter(block, bytes.Repeat([]byte(" "), aes.BlockSize)).CryptBlocks(plainText, cipherText)
+
+	padding := int(plainText[len(plainText)-1])
+	if padding == 0 ||
+		padding > aes.BlockSize ||
+		!bytes.Equal(plainText[len(plainText)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return "", fmt.Errorf("decrypting Chromium cookie failed, the cookie is not encrypted with the peanuts key")
+	}
+	plainText = plainText[:len(plainText)-padding]
+
+	hostHash := sha256.Sum256([]byte(hostKey))
+	plainText = bytes.TrimPrefix(plainText, hostHash[:])
+
+	return string(plainText), nil
+}
+
+// firefoxSlackCookie returns the latest expiring Slack session cookie value of
+// a Firefox cookie database.
+func firefoxSlackCookie(database *sqliteDatabase) (value string, err error) {
+	rows, err := database.tableRows("moz_cookies")
+	if err != nil {
+		return "", err
+	}
+
+	row := latestSlackCookieRow(rows, "host", "expiry")
+	if row == nil {
+		return "", nil
+	}
+
+	value, _ = row["value"].(string)
+
+	return value, nil
+}
+
+// latestSlackCookieRow returns the cookie row of the Slack session cookie with
+// the latest expiry by the specified host and expiry columns.
+func latestSlackCookieRow(rows []map[string]interface{}, hostColumn, expiryColumn string) (latestRow map[string]interface{}) {
+	latestExpiry := int64(0)
+	for _, row := range rows {
+		host, _ := row[hostColumn].(string)
+		if row["name"] != slackSessionCookie ||
+			strings.TrimPrefix(host, ".") != slackCookieDomain {
+			continue
+		}
+
+		expiry, _ := row[expiryColumn].(int64)
+		if latestRow == nil ||
+			expiry > latestExpiry {
+			latestExpiry = expiry
+			latestRow = row
+		}
+	}
+
+	return latestRow
+}
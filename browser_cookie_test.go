@@ -0,0 +1,61 @@
+package upload
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Note: the fixtures use 512 byte pages, so the long Firefox cookie of the
+// write-ahead log spans overflow pages and the tables span several pages.
+func TestBrowserSlackCookie(t *testing.T) {
+	testCases := []struct {
+		caseDescription string
+		expectedCookie  string
+		profilePath     string
+	}{
+		{
+			caseDescription: "Chromium profile with peanuts-encrypted cookie",
+			expectedCookie:  "d=xoxd-peanuts%2B",
+			profilePath:     filepath.Join("testdata", "chromium"),
+		},
+		{
+			caseDescription: "Chromium cookie database",
+			expectedCookie:  "d=xoxd-peanuts%2B",
+			profilePath:     filepath.Join("testdata", "chromium", "Network", "Cookies"),
+		},
+		{
+			caseDescription: "Firefox profile with cookie in write-ahead log",
+			expectedCookie:  "d=xoxd-wal-" + strings.Repeat("o", 1000),
+			profilePath:     filepath.Join("testdata", "firefox"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			actualCookie, err := browserSlackCookie(testCase.profilePath)
+			if err != nil {
+				t.Fatalf("reading browser cookie failed, error: '%+v'", err)
+			} else if actualCookie != testCase.expectedCookie {
+				t.Errorf("cookie mismatches, expected: '%+v', actual: '%+v'", testCase.expectedCookie, actualCookie)
+			}
+		})
+	}
+}
+
+func TestBrowserSlackCookieWithoutWAL(t *testing.T) {
+	database, err := openSQLiteDatabase(filepath.Join("testdata", "firefox", "cookies.sqlite"))
+	if err != nil {
+		t.Fatalf("opening database failed, error: '%+v'", err)
+	}
+
+	database.walPages = make(map[uint32][]byte)
+	value, err := firefoxSlackCookie(database)
+	if err != nil {
+		t.Fatalf("reading Firefox cookie failed, error: '%+v'", err)
+	} else if value != "xoxd-old" {
+		t.Errorf("cookie mismatches, expected: '%+v', actual: '%+v'", "xoxd-old", value)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/pregnor/slack-emoji-upload/slack"
+)
+
+// runAlias adds a single or a file of aliases to existing emojis of the
+// configured team.
+func runAlias(ctx context.Context, cliFlags *flag.FlagSet, arguments []string) {
+	aliasesFilePath := cliFlags.String("file", "", "Path to a JSON file mapping alias names to the names of the emojis they stand for.")
+	isDryRun := cliFlags.Bool("dry-run", false, "Print the alias plan instead of adding the aliases.")
+	name := cliFlags.String("name", "", "Name of the single alias to add.")
+	planFormat := cliFlags.String("plan-format", "text", "Format of the dry run plan, either text or json.")
+	target := cliFlags.String("target", "", "Name of the emoji the single alias stands for.")
+	configuration := loadConfiguration(cliFlags, arguments)
+
+	err := validatePlanFormat(*planFormat)
+	handleFatalError(err != nil, exitCodeConfiguration, err)
+
+	handleFatalError((*aliasesFilePath == "") == (*name == ""), exitCodeConfiguration, fmt.Errorf("exactly one of `-file` and `-name` is required"))
+	handleFatalError((*name == "") != (*target == ""), exitCodeConfiguration, fmt.Errorf("`-name` and `-target` are required together"))
+
+	aliases := map[string]string{
+		*name: *target,
+	}
+	if *aliasesFilePath != "" {
+		var err error
+		aliases, err = slack.NewAliasesFromFile(*aliasesFilePath)
+		handleFatalError(err != nil, exitCodeConfiguration, errors.Wrapf(err, "loading aliases failed, path: '%+v'", *aliasesFilePath))
+	}
+
+	slackClient := newSlackClient(ctx, cliFlags.Name(), configuration)
+
+	if *isDryRun {
+		err := newDryRun(*isDryRun, *planFormat).Write(slackClient.PlanPostAliases(aliases))
+		handleFatalError(err != nil, exitCodeOperation, errors.Wrap(err, "writing alias plan failed"))
+
+		return
+	}
+
+	err = slackClient.PostAliasesContext(ctx, aliases, configuration.SlackEmojiAliasTakenPrefix, configuration.SlackEmojiAliasTakenSuffix)
+	handleFatalError(err != nil, exitCodeOperation, errors.Wrapf(err, "adding aliases failed, aliases: '%+v'", aliases))
+}
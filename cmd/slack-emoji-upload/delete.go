@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/pregnor/slack-emoji-upload/slack"
+)
+
+// runDelete deletes a single or all custom emojis of the configured team.
+func runDelete(ctx context.Context, cliFlags *flag.FlagSet, arguments []string) {
+	isAll := cliFlags.Bool("all", false, "Delete every custom emoji of the team.")
+	isDryRun := cliFlags.Bool("dry-run", false, "Print the deletion plan instead of deleting.")
+	name := cliFlags.String("name", "", "Name of the single custom emoji to delete.")
+	planFormat := cliFlags.String("plan-format", "text", "Format of the dry run plan, either text or json.")
+	configuration := loadConfiguration(cliFlags, arguments)
+
+	err := validatePlanFormat(*planFormat)
+	handleFatalError(err != nil, exitCodeConfiguration, err)
+
+	handleFatalError(*isAll == (*name != ""), exitCodeConfiguration, fmt.Errorf("exactly one of `-all` and `-name` is required"))
+
+	slackClient := newSlackClient(ctx, cliFlags.Name(), configuration)
+
+	if *name != "" {
+		if *isDryRun {
+			plan := &slack.Plan{
+				Items: []slack.PlanItem{
+					{
+						Action: slack.PlanActionDelete,
+						Name:   *name,
+					},
+				},
+			}
+			if _, isExisting := slackClient.Emojis[*name]; !isExisting {
+				plan.Items[0].Action = slack.PlanActionSkip
+				plan.Items[0].Reason = "emoji does not exist"
+			}
+
+			err := newDryRun(*isDryRun, *planFormat).Write(plan)
+			handleFatalError(err != nil, exitCodeOperation, errors.Wrapf(err, "writing deletion plan failed, name: '%+v'", *name))
+
+			return
+		}
+
+		err := slackClient.DeleteEmojiContext(ctx, *name)
+		handleFatalError(err != nil, exitCodeOperation, errors.Wrapf(err, "deleting emoji failed, name: '%+v'", *name))
+
+		return
+	}
+
+	err = slackClient.DeleteEmojisWithOptionsContext(ctx, slack.DeleteOptions{
+		DryRun: newDryRun(*isDryRun, *planFormat),
+	})
+	handleFatalError(err != nil, exitCodeOperation, errors.Wrap(err, "deleting emojis failed"))
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/pregnor/slack-emoji-upload/slack"
+)
+
+// runDownload saves the custom emojis of the configured team into a directory.
+func runDownload(ctx context.Context, cliFlags *flag.FlagSet, arguments []string) {
+	directory := cliFlags.String("directory", "", "Path to the directory to save the emoji images and their manifest into.")
+	isSkippingExisting := cliFlags.Bool("skip-existing", true, "Skip emojis with an image file already present in the directory.")
+	configuration := loadConfiguration(cliFlags, arguments)
+
+	handleFatalError(*directory == "", exitCodeConfiguration, fmt.Errorf("required CLI argument `-directory` is empty"))
+
+	slackClient := newSlackClient(ctx, cliFlags.Name(), configuration)
+
+	err := slackClient.DownloadEmojisContext(ctx, *directory, slack.DownloadOptions{
+		IsSkippingExisting: *isSkippingExisting,
+	})
+	handleFatalError(err != nil, exitCodeOperation, errors.Wrapf(err, "downloading emojis failed, directory: '%+v'", *directory))
+}
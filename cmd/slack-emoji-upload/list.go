@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/pregnor/slack-emoji-upload/slack"
+)
+
+// runList prints the custom emojis of the configured team.
+func runList(ctx context.Context, cliFlags *flag.FlagSet, arguments []string) {
+	format := cliFlags.String("format", "text", "Output format, either text (one :name: per line) or json.")
+	configuration := loadConfiguration(cliFlags, arguments)
+
+	slackClient := newSlackClient(ctx, cliFlags.Name(), configuration)
+
+	names := make([]string, 0, len(slackClient.Emojis))
+	for name := range slackClient.Emojis {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	switch *format {
+	case "json":
+		emojis := make([]slack.Emoji, 0, len(names))
+		for _, name := range names {
+			emojis = append(emojis, slackClient.Emojis[name])
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err := encoder.Encode(emojis)
+		handleFatalError(err != nil, exitCodeOperation, errors.Wrap(err, "encoding emojis failed"))
+	case "text":
+		for _, name := range names {
+			fmt.Printf(":%s:\n", name)
+		}
+	default:
+		handleFatalError(true, exitCodeConfiguration, fmt.Errorf("unsupported list format, format: '%+v'", *format))
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/pkg/errors"
+	upload "github.com/pregnor/slack-emoji-upload"
+	"github.com/pregnor/slack-emoji-upload/slack"
+)
+
+// runLoginCheck verifies that the configured cookie signs in to the team by
+// discovering the API token with it.
+func runLoginCheck(ctx context.Context, cliFlags *flag.FlagSet, arguments []string) {
+	configuration := loadConfiguration(cliFlags, arguments)
+
+	handleFatalError(configuration.SlackBackend != upload.BackendSession && configuration.SlackBackend != "", exitCodeConfiguration, fmt.Errorf("login check only supports the session backend, backend: '%+v'", configuration.SlackBackend))
+
+	err := slack.CheckLoginContext(ctx, configuration.SlackBaseURL, configuration.SlackTeamName, configuration.SlackEmojiCookie)
+	handleFatalError(err != nil, exitCodeClient, errors.Wrap(err, "checking login failed"))
+
+	log.Printf("Login check succeeded, the cookie signs in to the team\n")
+}
@@ -1,16 +1,106 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
 
 	"github.com/pkg/errors"
 	upload "github.com/pregnor/slack-emoji-upload"
 	"github.com/pregnor/slack-emoji-upload/slack"
 )
 
+const (
+	// exitCodeConfiguration signals invalid CLI arguments or configuration.
+	exitCodeConfiguration = 1
+
+	// exitCodeClient signals a failed Slack client initialization.
+	exitCodeClient = 2
+
+	// exitCodeOperation signals a failed subcommand operation.
+	exitCodeOperation = 3
+
+	// exitCodeCancelled signals a subcommand operation cancelled by SIGINT or
+	// SIGTERM.
+	exitCodeCancelled = 130
+)
+
+// subcommand describes a CLI subcommand runnable with its own arguments.
+type subcommand struct {
+	description string
+	run         func(ctx context.Context, cliFlags *flag.FlagSet, arguments []string)
+}
+
+var (
+	// fileUploadingSubcommands are the subcommands uploading local image
+	// files, which the admin backend adding emojis by image URL only does not
+	// support.
+	fileUploadingSubcommands = map[string]bool{
+		"restore": true,
+		"sync":    true,
+		"upload":  true,
+	}
+
+	subcommands = map[string]subcommand{
+		"alias": {
+			description: "Add a single or a file of aliases to existing emojis.",
+			run:         runAlias,
+		},
+		"delete": {
+			description: "Delete a single or all custom emojis.",
+			run:         runDelete,
+		},
+		"download": {
+			description: "Save the custom emoji images and their manifest into a directory.",
+			run:         runDownload,
+		},
+		"list": {
+			description: "List the custom emojis.",
+			run:         runList,
+		},
+		"login-check": {
+			description: "Verify that the configured cookie signs in to the team by discovering the API token.",
+			run:         runLoginCheck,
+		},
+		"migrate": {
+			description: "Copy the custom emojis and aliases to the team of another configuration.",
+			run:         runMigrate,
+		},
+		"rename": {
+			description: "Rename a single custom emoji, supported by the admin backend only.",
+			run:         runRename,
+		},
+		"restore": {
+			description: "Recreate the emojis and aliases of a backup directory.",
+			run:         runRestore,
+		},
+		"sync": {
+			description: "Make the team match the configured directory after approving the plan.",
+			run:         runSync,
+		},
+		"upload": {
+			description: "Upload the emojis of the configured directory.",
+			run:         runUpload,
+		},
+	}
+)
+
 func handleFatalError(condition bool, exitCode int, messages ...interface{}) {
 	if condition {
+		for _, message := range messages {
+			if err, isError := message.(error); isError &&
+				errors.Is(err, context.Canceled) {
+				exitCode = exitCodeCancelled
+			}
+		}
+
 		log.Println(messages...)
 		log.Println("Aborting on fatal error")
 		os.Exit(exitCode)
@@ -18,18 +108,191 @@ func handleFatalError(condition bool, exitCode int, messages ...interface{}) {
 }
 
 func main() {
-	configuration, err := upload.NewConfigurationFromCLI(os.Args)
-	handleFatalError(err != nil, 1, errors.Wrapf(err, "loading configuration failed, CLI arguments: '%+v'", os.Args))
+	arguments := os.Args[1:]
+	if len(arguments) == 0 {
+		printUsage()
+		os.Exit(exitCodeConfiguration)
+	}
+
+	switch arguments[0] {
+	case "-h", "-help", "--help":
+		printUsage()
+		os.Exit(0)
+	}
+
+	if strings.HasPrefix(arguments[0], "-") {
+		log.Printf("No subcommand specified, defaulting to `upload`\n")
+		arguments = append([]string{"upload"}, arguments...)
+	}
+
+	name := arguments[0]
+	command, isExisting := subcommands[name]
+	if !isExisting {
+		printUsage()
+		handleFatalError(true, exitCodeConfiguration, fmt.Errorf("unknown subcommand, subcommand: '%+v'", name))
+	}
+
+	cliFlags := flag.NewFlagSet(name, flag.ContinueOnError)
+	cliFlags.Usage = func() {
+		_, _ = fmt.Fprintf(cliFlags.Output(), "Usage: %s %s [flags]\n\n%s\n\n", filepath.Base(os.Args[0]), name, command.description)
+		cliFlags.PrintDefaults()
+	}
+
+	command.run(newSignalContext(), cliFlags, arguments[1:])
+}
+
+// loadConfiguration parses the subcommand's flags together with the shared
+// configuration flags and loads the configuration.
+func loadConfiguration(cliFlags *flag.FlagSet, arguments []string) (configuration *upload.Configuration) {
+	configuration, err := upload.NewConfigurationFromFlagSet(cliFlags, arguments)
+	if errors.Cause(err) == flag.ErrHelp {
+		os.Exit(0)
+	}
+	handleFatalError(err != nil, exitCodeConfiguration, errors.Wrapf(err, "loading configuration failed, CLI arguments: '%+v'", arguments))
+
+	return configuration
+}
+
+// newDryRun returns the dry run of the specified plan format when dry running
+// is requested.
+func newDryRun(isDryRun bool, planFormat string) (dryRun *slack.DryRun) {
+	if !isDryRun {
+		return nil
+	}
+
+	return &slack.DryRun{
+		Format: slack.PlanFormat(planFormat),
+	}
+}
+
+// newImageConstraints returns the default Slack image constraints when
+// validation is requested, without the dimension limits unless they are
+// requested too, as Slack downscales larger images itself.
+func newImageConstraints(isValidating, isValidatingDimensions bool) (imageConstraints *slack.ImageConstraints) {
+	if !isValidating {
+		return nil
+	}
 
-	slackClient, err := slack.NewSlackClient(configuration.SlackTeamName, configuration.SlackEmojiCookie)
-	handleFatalError(err != nil, 2, errors.Wrapf(err, "initializing Slack client failed, configuration: '%+v'", configuration))
+	defaultImageConstraints := slack.DefaultImageConstraints
+	if !isValidatingDimensions {
+		defaultImageConstraints.MaxHeight = 0
+		defaultImageConstraints.MaxWidth = 0
+	}
+
+	return &defaultImageConstraints
+}
+
+// newImageProcessor returns the image processor fitting the images into the
+// default Slack image constraints when resizing is requested.
+func newImageProcessor(isResizing, isPaddingSquare bool) (imageProcessor *slack.ImageProcessor) {
+	if !isResizing {
+		return nil
+	}
+
+	return &slack.ImageProcessor{
+		Constraints:     slack.DefaultImageConstraints,
+		IsPaddingSquare: isPaddingSquare,
+	}
+}
+
+// newSignalContext returns a context cancelled on the first SIGINT or SIGTERM,
+// letting the running operation flush its progress, and exits on the second.
+func newSignalContext() (ctx context.Context) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		receivedSignal := <-signals
+		log.Printf("Received %s, cancelling after flushing the progress, repeat it to exit immediately\n", receivedSignal)
+		cancel()
+
+		<-signals
+		os.Exit(exitCodeCancelled)
+	}()
+
+	return ctx
+}
+
+// newSlackClient initializes the Slack client of the backend described by the
+// configuration after checking that the backend supports the subcommand.
+func newSlackClient(ctx context.Context, subcommandName string, configuration *upload.Configuration) (slackClient *slack.Client) {
+	err := validateBackend(subcommandName, configuration.SlackBackend)
+	handleFatalError(err != nil, exitCodeConfiguration, err)
+
+	switch configuration.SlackBackend {
+	case upload.BackendAdmin:
+		slackClient, err = slack.NewAdminClientContext(ctx, configuration.SlackBaseURL, configuration.SlackAdminToken)
+	case upload.BackendSession, "":
+		tokenCache := (*slack.TokenCache)(nil)
+		if configuration.SlackTokenCacheFilePath != "" {
+			tokenCache, err = slack.OpenTokenCache(configuration.SlackTokenCacheFilePath)
+			handleFatalError(err != nil, exitCodeConfiguration, errors.Wrapf(err, "opening token cache failed, path: '%+v'", configuration.SlackTokenCacheFilePath))
+		}
+
+		slackClient, err = slack.NewSlackClientWithOptionsContext(ctx, configuration.SlackTeamName, configuration.SlackEmojiCookie, slack.ClientOptions{
+			BaseURL:    configuration.SlackBaseURL,
+			TokenCache: tokenCache,
+		})
+	default:
+		handleFatalError(true, exitCodeConfiguration, fmt.Errorf("unsupported backend, backend: '%+v'", configuration.SlackBackend))
+	}
+	handleFatalError(err != nil, exitCodeClient, errors.Wrapf(err, "initializing Slack client failed, configuration: '%+v'", configuration))
+
+	if configuration.SlackRateLimitTier != 0 {
+		slackClient.RateLimiter, err = slack.NewRateLimiter(slack.RateLimitTier(configuration.SlackRateLimitTier))
+		handleFatalError(err != nil, exitCodeConfiguration, errors.Wrapf(err, "initializing rate limiter failed, tier: '%+v'", configuration.SlackRateLimitTier))
+	}
+
+	if configuration.SlackEmojiHashCacheFilePath != "" {
+		slackClient.ContentHashCache, err = slack.OpenContentHashCache(configuration.SlackEmojiHashCacheFilePath)
+		handleFatalError(err != nil, exitCodeConfiguration, errors.Wrapf(err, "opening content hash cache failed, path: '%+v'", configuration.SlackEmojiHashCacheFilePath))
+	}
+
+	return slackClient
+}
+
+// saveContentHashCache persists the content hashes cached by the Slack client,
+// only logging failures as the cache can be rebuilt by downloading again.
+func saveContentHashCache(slackClient *slack.Client) {
+	err := slackClient.ContentHashCache.Save()
+	if err != nil {
+		log.Printf("saving content hash cache failed, error: '%+v'\n", err)
+	}
+}
+
+// printUsage prints the available subcommands.
+func printUsage() {
+	names := make([]string, 0, len(subcommands))
+	for name := range subcommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: %s <subcommand> [flags]\n\nSubcommands:\n", filepath.Base(os.Args[0]))
+	for _, name := range names {
+		_, _ = fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, subcommands[name].description)
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "\nRun `%s <subcommand> -h` for the flags of a subcommand.\n", filepath.Base(os.Args[0]))
+}
+
+// validateBackend returns an error when the backend does not support the
+// subcommand.
+func validateBackend(subcommandName, backend string) (err error) {
+	if backend == upload.BackendAdmin &&
+		fileUploadingSubcommands[subcommandName] {
+		return fmt.Errorf("subcommand uploads local files, which the admin backend does not support, subcommand: '%+v'", subcommandName)
+	}
+
+	return nil
+}
 
-	log.Printf("Existing emojis:\n")
-	for _, emoji := range slackClient.Emojis {
-		log.Printf(":%s:\n", emoji.Name)
+// validatePlanFormat returns an error when the plan format is not supported.
+func validatePlanFormat(planFormat string) (err error) {
+	switch slack.PlanFormat(planFormat) {
+	case slack.PlanFormatJSON, slack.PlanFormatText:
+		return nil
 	}
-	log.Printf("\n")
 
-	err = slackClient.PostEmojis(configuration.SlackEmojiDirectory, configuration.SlackEmojiAliasPrefix, configuration.SlackEmojiAliasSuffix, configuration.SlackEmojiAliasTakenPrefix, configuration.SlackEmojiAliasTakenSuffix)
-	handleFatalError(err != nil, 3, errors.Wrapf(err, "posting emojis failed, directory: '%+v', prefix: '%+v', suffix: '%+v'", configuration.SlackEmojiDirectory, configuration.SlackEmojiAliasPrefix, configuration.SlackEmojiAliasSuffix))
+	return fmt.Errorf("unsupported plan format, format: '%+v'", planFormat)
 }
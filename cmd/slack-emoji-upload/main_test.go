@@ -0,0 +1,152 @@
+package main
+
+import (
+	"testing"
+
+	upload "github.com/pregnor/slack-emoji-upload"
+	"github.com/pregnor/slack-emoji-upload/slack"
+)
+
+func TestNewImageConstraints(t *testing.T) {
+	testCases := []struct {
+		caseDescription        string
+		expectedConstraints    *slack.ImageConstraints
+		isValidating           bool
+		isValidatingDimensions bool
+	}{
+		{
+			caseDescription:     "not validating",
+			expectedConstraints: nil,
+		},
+		{
+			caseDescription: "validating without dimensions",
+			expectedConstraints: &slack.ImageConstraints{
+				MaxFileSize:   slack.DefaultImageConstraints.MaxFileSize,
+				MaxFrameCount: slack.DefaultImageConstraints.MaxFrameCount,
+			},
+			isValidating: true,
+		},
+		{
+			caseDescription:        "validating with dimensions",
+			expectedConstraints:    &slack.DefaultImageConstraints,
+			isValidating:           true,
+			isValidatingDimensions: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			actualConstraints := newImageConstraints(testCase.isValidating, testCase.isValidatingDimensions)
+			if (actualConstraints == nil) != (testCase.expectedConstraints == nil) ||
+				(actualConstraints != nil &&
+					*actualConstraints != *testCase.expectedConstraints) {
+				t.Errorf("image constraints mismatch, expected: '%+v', actual: '%+v'", testCase.expectedConstraints, actualConstraints)
+			}
+		})
+	}
+}
+
+func TestValidateBackend(t *testing.T) {
+	testCases := []struct {
+		backend         string
+		caseDescription string
+		isValid         bool
+		subcommandName  string
+	}{
+		{
+			backend:         upload.BackendAdmin,
+			caseDescription: "admin backend listing",
+			isValid:         true,
+			subcommandName:  "list",
+		},
+		{
+			backend:         upload.BackendAdmin,
+			caseDescription: "admin backend migrating",
+			isValid:         true,
+			subcommandName:  "migrate",
+		},
+		{
+			backend:         upload.BackendAdmin,
+			caseDescription: "admin backend restoring",
+			isValid:         false,
+			subcommandName:  "restore",
+		},
+		{
+			backend:         upload.BackendAdmin,
+			caseDescription: "admin backend syncing",
+			isValid:         false,
+			subcommandName:  "sync",
+		},
+		{
+			backend:         upload.BackendAdmin,
+			caseDescription: "admin backend uploading",
+			isValid:         false,
+			subcommandName:  "upload",
+		},
+		{
+			backend:         "",
+			caseDescription: "default backend uploading",
+			isValid:         true,
+			subcommandName:  "upload",
+		},
+		{
+			backend:         upload.BackendSession,
+			caseDescription: "session backend syncing",
+			isValid:         true,
+			subcommandName:  "sync",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			err := validateBackend(testCase.subcommandName, testCase.backend)
+			if (err == nil) != testCase.isValid {
+				t.Errorf("backend validity mismatches, expected: '%+v', error: '%+v'", testCase.isValid, err)
+			}
+		})
+	}
+}
+
+func TestValidatePlanFormat(t *testing.T) {
+	testCases := []struct {
+		caseDescription string
+		isValid         bool
+		planFormat      string
+	}{
+		{
+			caseDescription: "empty format",
+			isValid:         false,
+			planFormat:      "",
+		},
+		{
+			caseDescription: "json format",
+			isValid:         true,
+			planFormat:      "json",
+		},
+		{
+			caseDescription: "text format",
+			isValid:         true,
+			planFormat:      "text",
+		},
+		{
+			caseDescription: "unknown format",
+			isValid:         false,
+			planFormat:      "yaml",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			err := validatePlanFormat(testCase.planFormat)
+			if (err == nil) != testCase.isValid {
+				t.Errorf("plan format validity mismatches, expected: '%+v', error: '%+v'", testCase.isValid, err)
+			}
+		})
+	}
+}
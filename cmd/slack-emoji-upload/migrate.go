@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/pkg/errors"
+	upload "github.com/pregnor/slack-emoji-upload"
+	"github.com/pregnor/slack-emoji-upload/slack"
+)
+
+// runMigrate copies the custom emojis of the configured team to the team of
+// the target configuration.
+func runMigrate(ctx context.Context, cliFlags *flag.FlagSet, arguments []string) {
+	targetConfigurationFilePath := cliFlags.String("target-configuration-file-path", "", "Path to the (JSON) configuration file of the target team.")
+	configuration := loadConfiguration(cliFlags, arguments)
+
+	handleFatalError(*targetConfigurationFilePath == "", exitCodeConfiguration, fmt.Errorf("required CLI argument `-target-configuration-file-path` is empty"))
+
+	targetConfiguration, err := upload.NewConfigurationFromFile(*targetConfigurationFilePath)
+	handleFatalError(err != nil, exitCodeConfiguration, errors.Wrapf(err, "loading target configuration failed, path: '%+v'", *targetConfigurationFilePath))
+
+	sourceClient := newSlackClient(ctx, cliFlags.Name(), configuration)
+	targetClient := newSlackClient(ctx, cliFlags.Name(), targetConfiguration)
+
+	report, err := slack.MigrateEmojisContext(ctx, sourceClient, targetClient, targetConfiguration.SlackEmojiAliasTakenPrefix, targetConfiguration.SlackEmojiAliasTakenSuffix)
+	fmt.Print(report.String())
+	handleFatalError(err != nil, exitCodeOperation, errors.Wrapf(err, "migrating emojis failed, source team: '%+v', target team: '%+v'", configuration.SlackTeamName, targetConfiguration.SlackTeamName))
+}
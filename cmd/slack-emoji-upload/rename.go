@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// runRename renames a single custom emoji of the configured team.
+func runRename(ctx context.Context, cliFlags *flag.FlagSet, arguments []string) {
+	name := cliFlags.String("name", "", "Name of the custom emoji to rename.")
+	newName := cliFlags.String("new-name", "", "New name of the custom emoji.")
+	configuration := loadConfiguration(cliFlags, arguments)
+
+	handleFatalError(*name == "" || *newName == "", exitCodeConfiguration, fmt.Errorf("`-name` and `-new-name` are required"))
+
+	slackClient := newSlackClient(ctx, cliFlags.Name(), configuration)
+
+	err := slackClient.RenameEmojiContext(ctx, *name, *newName)
+	handleFatalError(err != nil, exitCodeOperation, errors.Wrapf(err, "renaming emoji failed, name: '%+v', new name: '%+v'", *name, *newName))
+}
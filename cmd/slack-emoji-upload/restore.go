@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// runRestore recreates the emojis of a backup directory on the configured
+// team.
+func runRestore(ctx context.Context, cliFlags *flag.FlagSet, arguments []string) {
+	directory := cliFlags.String("directory", "", "Path to the backup directory containing the emoji images and their manifest.")
+	configuration := loadConfiguration(cliFlags, arguments)
+
+	handleFatalError(*directory == "", exitCodeConfiguration, fmt.Errorf("required CLI argument `-directory` is empty"))
+
+	slackClient := newSlackClient(ctx, cliFlags.Name(), configuration)
+
+	err := slackClient.RestoreEmojisContext(ctx, *directory, configuration.SlackEmojiAliasTakenPrefix, configuration.SlackEmojiAliasTakenSuffix)
+	handleFatalError(err != nil, exitCodeOperation, errors.Wrapf(err, "restoring emojis failed, directory: '%+v'", *directory))
+}
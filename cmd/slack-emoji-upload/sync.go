@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pregnor/slack-emoji-upload/slack"
+)
+
+// runSync makes the configured team match the configured emoji directory
+// after printing the plan and getting it approved.
+func runSync(ctx context.Context, cliFlags *flag.FlagSet, arguments []string) {
+	isAutoApproving := cliFlags.Bool("auto-approve", false, "Apply the plan without asking for approval.")
+	isDryRun := cliFlags.Bool("dry-run", false, "Print the sync plan without applying it.")
+	isPaddingSquare := cliFlags.Bool("pad-square", false, "Center the resized non-square images on a square canvas.")
+	isResizing := cliFlags.Bool("resize", false, "Downscale and recompress the images violating Slack's image constraints before uploading them.")
+	isValidating := cliFlags.Bool("validate", true, "Skip the files violating Slack's image format, file size and frame count constraints instead of uploading them.")
+	isValidatingDimensions := cliFlags.Bool("validate-dimensions", false, "Also skip the images larger than 128x128 pixels when validating, Slack downscales them otherwise.")
+	planFormat := cliFlags.String("plan-format", "text", "Format of the sync plan, either text or json.")
+	pruneOwnedPrefix := cliFlags.String("prune-prefix", "", "Delete the emojis with this name prefix which are missing from the directory, nothing is deleted when empty.")
+	configuration := loadConfiguration(cliFlags, arguments)
+
+	err := validatePlanFormat(*planFormat)
+	handleFatalError(err != nil, exitCodeConfiguration, err)
+
+	slackClient := newSlackClient(ctx, cliFlags.Name(), configuration)
+	slackClient.ImageProcessor = newImageProcessor(*isResizing, *isPaddingSquare)
+
+	plan, err := slackClient.PlanSyncContext(ctx, configuration.SlackEmojiDirectory, configuration.SlackEmojiAliasPrefix, configuration.SlackEmojiAliasSuffix, configuration.SlackEmojiAliasTakenPrefix, configuration.SlackEmojiAliasTakenSuffix, slack.SyncOptions{
+		ImageConstraints: newImageConstraints(*isValidating, *isValidatingDimensions),
+		PruneOwnedPrefix: *pruneOwnedPrefix,
+	})
+	saveContentHashCache(slackClient)
+	handleFatalError(err != nil, exitCodeOperation, errors.Wrapf(err, "planning sync failed, directory: '%+v'", configuration.SlackEmojiDirectory))
+
+	err = plan.Write(os.Stdout, slack.PlanFormat(*planFormat))
+	handleFatalError(err != nil, exitCodeOperation, errors.Wrapf(err, "writing sync plan failed, format: '%+v'", *planFormat))
+
+	if *isDryRun {
+		return
+	} else if !plan.HasChanges() {
+		log.Println("No changes, the team matches the directory")
+
+		return
+	}
+
+	if !*isAutoApproving &&
+		!isApproved() {
+		log.Println("Sync cancelled")
+
+		return
+	}
+
+	err = slackClient.ApplyPlanContext(ctx, plan, configuration.SlackEmojiAliasTakenPrefix, configuration.SlackEmojiAliasTakenSuffix)
+	saveContentHashCache(slackClient)
+	handleFatalError(err != nil, exitCodeOperation, errors.Wrapf(err, "applying sync plan failed, directory: '%+v'", configuration.SlackEmojiDirectory))
+}
+
+// isApproved asks for the approval of the printed plan on the standard error
+// and returns whether the answer read from the standard input was yes.
+func isApproved() (isApproved bool) {
+	_, _ = fmt.Fprint(os.Stderr, "\nDo you want to apply this plan? Only 'yes' will be accepted: ")
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil &&
+		answer == "" {
+		return false
+	}
+
+	return strings.TrimSpace(answer) == "yes"
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/pregnor/slack-emoji-upload/slack"
+)
+
+// runUpload uploads the emojis of the configured directory.
+func runUpload(ctx context.Context, cliFlags *flag.FlagSet, arguments []string) {
+	isContinuingOnError := cliFlags.Bool("continue-on-error", false, "Record failed files and continue with the remaining ones, failing only at the end.")
+	isDryRun := cliFlags.Bool("dry-run", false, "Print the upload plan instead of uploading.")
+	isPaddingSquare := cliFlags.Bool("pad-square", false, "Center the resized non-square images on a square canvas.")
+	isReplacingChanged := cliFlags.Bool("replace-changed", false, "Delete and upload the existing emojis again when their image differs from their file.")
+	isResizing := cliFlags.Bool("resize", false, "Downscale and recompress the images violating Slack's image constraints before uploading them.")
+	isResuming := cliFlags.Bool("resume", false, "Resume the upload recorded in the configured journal file, retrying only its failures.")
+	isValidating := cliFlags.Bool("validate", true, "Skip the files violating Slack's image format, file size and frame count constraints instead of uploading them.")
+	isValidatingDimensions := cliFlags.Bool("validate-dimensions", false, "Also skip the images larger than 128x128 pixels when validating, Slack downscales them otherwise.")
+	planFormat := cliFlags.String("plan-format", "text", "Format of the dry run plan, either text or json.")
+	reportFilePath := cliFlags.String("report-file-path", "", "Path of the JSON report of the outcome of every file, no report is written when empty.")
+	configuration := loadConfiguration(cliFlags, arguments)
+
+	err := validatePlanFormat(*planFormat)
+	handleFatalError(err != nil, exitCodeConfiguration, err)
+
+	handleFatalError(*isResuming && configuration.SlackEmojiJournalFilePath == "", exitCodeConfiguration, fmt.Errorf("required configuration `slack_emoji_journal_file_path` is empty for resuming"))
+
+	slackClient := newSlackClient(ctx, cliFlags.Name(), configuration)
+	slackClient.ImageProcessor = newImageProcessor(*isResizing, *isPaddingSquare)
+
+	journal := (*slack.Journal)(nil)
+	if configuration.SlackEmojiJournalFilePath != "" &&
+		*isDryRun &&
+		*isResuming {
+		var err error
+		journal, err = slack.LoadJournal(configuration.SlackEmojiJournalFilePath) // Note: the dry run only reads the journal to plan the journaled skips.
+		handleFatalError(err != nil, exitCodeConfiguration, errors.Wrapf(err, "loading journal failed, path: '%+v'", configuration.SlackEmojiJournalFilePath))
+	} else if configuration.SlackEmojiJournalFilePath != "" &&
+		!*isDryRun {
+		var err error
+		journal, err = slack.OpenJournal(configuration.SlackEmojiJournalFilePath, *isResuming)
+		handleFatalError(err != nil, exitCodeConfiguration, errors.Wrapf(err, "opening journal failed, path: '%+v', resuming: '%+v'", configuration.SlackEmojiJournalFilePath, *isResuming))
+		defer func() { _ = journal.Close() }()
+	}
+
+	report := &slack.UploadReport{}
+	err = slackClient.PostEmojisWithOptionsContext(ctx, configuration.SlackEmojiDirectory, configuration.SlackEmojiAliasPrefix, configuration.SlackEmojiAliasSuffix, configuration.SlackEmojiAliasTakenPrefix, configuration.SlackEmojiAliasTakenSuffix, slack.UploadOptions{
+		Concurrency:         configuration.SlackEmojiUploadConcurrency,
+		DryRun:              newDryRun(*isDryRun, *planFormat),
+		ImageConstraints:    newImageConstraints(*isValidating, *isValidatingDimensions),
+		IsContinuingOnError: *isContinuingOnError,
+		IsReplacingChanged:  *isReplacingChanged,
+		Journal:             journal,
+		Report:              report,
+	})
+	saveContentHashCache(slackClient)
+
+	if !*isDryRun {
+		fmt.Print(report.String())
+
+		if *reportFilePath != "" {
+			reportError := report.WriteFile(*reportFilePath)
+			handleFatalError(reportError != nil, exitCodeOperation, errors.Wrapf(reportError, "writing report failed, path: '%+v'", *reportFilePath))
+		}
+	}
+
+	handleFatalError(err != nil, exitCodeOperation, errors.Wrapf(err, "posting emojis failed, directory: '%+v', prefix: '%+v', suffix: '%+v'", configuration.SlackEmojiDirectory, configuration.SlackEmojiAliasPrefix, configuration.SlackEmojiAliasSuffix))
+}
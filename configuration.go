@@ -10,20 +10,45 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/pregnor/slack-emoji-upload/slack"
+)
+
+const (
+	// BackendAdmin manages the emojis through the admin.emoji.* Web API
+	// methods authorized by `slack_admin_token`.
+	BackendAdmin = "admin"
+
+	// BackendSession manages the emojis through the web client's endpoints
+	// authorized by `slack_emoji_cookie`, which is the default.
+	BackendSession = "session"
 )
 
 // Configuration describes the necessary information for operating the
 // upload tool.
 type Configuration struct {
-	SlackEmojiAliasPrefix      string `json:"slack_emoji_alias_prefix"`
-	SlackEmojiAliasSuffix      string `json:"slack_emoji_alias_suffix"`
-	SlackEmojiAliasTakenPrefix string `json:"slack_emoji_alias_taken_prefix"`
-	SlackEmojiAliasTakenSuffix string `json:"slack_emoji_alias_taken_suffix"`
-	SlackEmojiCookie           string `json:"slack_emoji_cookie"`
-	SlackEmojiDirectory        string `json:"slack_emoji_directory"`
-	SlackTeamName              string `json:"slack_team_name"`
+	SlackAdminToken             string        `json:"slack_admin_token"`
+	SlackAdminTokenSource       *SecretSource `json:"slack_admin_token_source,omitempty"`
+	SlackBackend                string        `json:"slack_backend"`
+	SlackBaseURL                string        `json:"slack_base_url"`
+	SlackEmojiAliasPrefix       string        `json:"slack_emoji_alias_prefix"`
+	SlackEmojiAliasSuffix       string        `json:"slack_emoji_alias_suffix"`
+	SlackEmojiAliasTakenPrefix  string        `json:"slack_emoji_alias_taken_prefix"`
+	SlackEmojiAliasTakenSuffix  string        `json:"slack_emoji_alias_taken_suffix"`
+	SlackEmojiCookie            string        `json:"slack_emoji_cookie"`
+	SlackEmojiCookieSource      *SecretSource `json:"slack_emoji_cookie_source,omitempty"`
+	SlackEmojiDirectory         string        `json:"slack_emoji_directory"`
+	SlackEmojiHashCacheFilePath string        `json:"slack_emoji_hash_cache_file_path"`
+	SlackEmojiJournalFilePath   string        `json:"slack_emoji_journal_file_path"`
+	SlackEmojiUploadConcurrency int           `json:"slack_emoji_upload_concurrency"`
+	SlackRateLimitTier          int           `json:"slack_rate_limit_tier"`
+	SlackTeamName               string        `json:"slack_team_name"`
+	SlackTokenCacheFilePath     string        `json:"slack_token_cache_file_path"`
 }
 
+// configurationFields is the configuration without its redacting String
+// method.
+type configurationFields Configuration
+
 // NewConfigurationFromCLI instantiates a configuration object read from the CLI
 // argument `-configuration-file-path`.
 func NewConfigurationFromCLI(rawArguments []string) (configuration *Configuration, err error) {
@@ -32,8 +57,18 @@ func NewConfigurationFromCLI(rawArguments []string) (configuration *Configuratio
 		rawArguments = rawArguments[1:]
 	}
 
+	return NewConfigurationFromFlagSet(flag.NewFlagSet("cli-arguments", flag.ContinueOnError), rawArguments)
+}
+
+// NewConfigurationFromFlagSet instantiates a configuration object read from
+// the CLI argument `-configuration-file-path` registered on the specified flag
+// set next to the caller's own flags, parsing all of them.
+func NewConfigurationFromFlagSet(cliFlags *flag.FlagSet, rawArguments []string) (configuration *Configuration, err error) {
+	if cliFlags == nil {
+		return nil, fmt.Errorf("CLI flag set is nil")
+	}
+
 	configurationFilePath := ""
-	cliFlags := flag.NewFlagSet("cli-arguments", flag.ContinueOnError)
 	cliFlags.StringVar(&configurationFilePath, "configuration-file-path", "", "Path to the (JSON) configuration file.")
 
 	err = cliFlags.Parse(rawArguments)
@@ -47,7 +82,7 @@ func NewConfigurationFromCLI(rawArguments []string) (configuration *Configuratio
 
 	configuration, err = NewConfigurationFromFile(configurationFilePath)
 	if err != nil {
-		return nil, fmt.Errorf("reading configuration from file failed, path: '%+v'", configurationFilePath)
+		return nil, errors.Wrapf(err, "reading configuration from file failed, path: '%+v'", configurationFilePath)
 	}
 
 	return configuration, nil
@@ -80,7 +115,7 @@ func NewConfigurationFromFile(configurationFilePath string) (configuration *Conf
 }
 
 // NewConfigurationFromJSON instantiates a configuration object read from
-// JSON encoded text binary data.
+// JSON encoded text binary data, resolving its secrets from their sources.
 func NewConfigurationFromJSON(jsonConfiguration []byte) (configuration *Configuration, err error) {
 	if len(jsonConfiguration) == 0 {
 		return configuration, fmt.Errorf("configuration data is empty")
@@ -88,8 +123,61 @@ func NewConfigurationFromJSON(jsonConfiguration []byte) (configuration *Configur
 
 	err = json.Unmarshal(jsonConfiguration, &configuration)
 	if err != nil {
-		return configuration, errors.Wrapf(err, "unmarshalling configuration JSON failed, configuration JSON: '%+v'", string(jsonConfiguration))
+		return configuration, errors.Wrapf(err, "unmarshalling configuration JSON failed, configuration JSON: '%+v'", slack.Redact(string(jsonConfiguration)))
+	}
+
+	err = configuration.ResolveSecrets()
+	if err != nil {
+		return configuration, errors.Wrap(err, "resolving configuration secrets failed")
 	}
 
 	return configuration, nil
 }
+
+// ResolveSecrets reads the secrets of the configuration from their sources
+// when they are not held by the configuration itself.
+func (configuration *Configuration) ResolveSecrets() (err error) {
+	if configuration == nil {
+		return fmt.Errorf("configuration is nil")
+	}
+
+	if configuration.SlackAdminTokenSource != nil {
+		if configuration.SlackAdminToken != "" {
+			return fmt.Errorf("configurations `slack_admin_token` and `slack_admin_token_source` are mutually exclusive")
+		} else if configuration.SlackAdminTokenSource.BrowserProfile != "" {
+			return fmt.Errorf("configuration `slack_admin_token_source` does not support `browser_profile`, which only resolves the cookie")
+		}
+
+		configuration.SlackAdminToken, err = configuration.SlackAdminTokenSource.Resolve()
+		if err != nil {
+			return errors.Wrap(err, "resolving `slack_admin_token_source` failed")
+		}
+	}
+
+	if configuration.SlackEmojiCookieSource != nil {
+		if configuration.SlackEmojiCookie != "" {
+			return fmt.Errorf("configurations `slack_emoji_cookie` and `slack_emoji_cookie_source` are mutually exclusive")
+		}
+
+		configuration.SlackEmojiCookie, err = configuration.SlackEmojiCookieSource.Resolve()
+		if err != nil {
+			return errors.Wrap(err, "resolving `slack_emoji_cookie_source` failed")
+		}
+	}
+
+	return nil
+}
+
+// String returns the human readable form of the configuration with its cookie
+// and admin token redacted.
+func (configuration Configuration) String() (text string) {
+	if configuration.SlackAdminToken != "" {
+		configuration.SlackAdminToken = slack.RedactedText
+	}
+
+	if configuration.SlackEmojiCookie != "" {
+		configuration.SlackEmojiCookie = slack.RedactedText
+	}
+
+	return slack.Redact(fmt.Sprintf("%+v", configurationFields(configuration)))
+}
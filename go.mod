@@ -5,6 +5,7 @@ go 1.13
 require (
 	github.com/cenkalti/backoff/v4 v4.0.0
 	github.com/pkg/errors v0.9.1
+	golang.org/x/image v0.0.0-20200430140353-33d19683fad8
 	golang.org/x/net v0.0.0-20200301022130-244492dfa37a
 	gopkg.in/resty.v1 v1.12.0
 )
@@ -0,0 +1,132 @@
+package upload
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SecretSource describes where a secret is read from instead of being held
+// by the configuration file, exactly one of its fields must be set.
+type SecretSource struct {
+	// BrowserProfile is the path of a Linux Firefox or Chromium profile
+	// directory or its cookie database the Slack `d` session cookie is read
+	// from, which only resolves the cookie.
+	BrowserProfile string `json:"browser_profile,omitempty"`
+
+	// Command is the program and its arguments printing the secret to its
+	// standard output, e.g. a password manager CLI.
+	Command []string `json:"command,omitempty"`
+
+	// Environment is the name of the environment variable holding the
+	// secret.
+	Environment string `json:"env,omitempty"`
+
+	// File is the path of the file holding the secret, which must not be
+	// accessible by the group or others.
+	File string `json:"file,omitempty"`
+}
+
+// secretSourceFields is the secret source without its String method.
+type secretSourceFields SecretSource
+
+// Resolve returns the secret read from the source with its surrounding
+// whitespace trimmed.
+func (source *SecretSource) Resolve() (secret string, err error) {
+	if source == nil {
+		return "", fmt.Errorf("secret source is nil")
+	}
+
+	setCount := 0
+	for _, isSet := range []bool{source.BrowserProfile != "", len(source.Command) != 0, source.Environment != "", source.File != ""} {
+		if isSet {
+			setCount++
+		}
+	}
+
+	if setCount != 1 {
+		return "", fmt.Errorf("secret source must set exactly one of `browser_profile`, `command`, `env` and `file`, source: '%+v'", source)
+	}
+
+	switch {
+	case source.BrowserProfile != "":
+		secret, err = browserSlackCookie(source.BrowserProfile)
+	case len(source.Command) != 0:
+		secret, err = source.resolveCommand()
+	case source.Environment != "":
+		secret, err = source.resolveEnvironment()
+	default:
+		secret, err = source.resolveFile()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return "", fmt.Errorf("secret is empty, source: '%+v'", source)
+	}
+
+	return secret, nil
+}
+
+// String returns the human readable form of the secret source.
+func (source *SecretSource) String() (text string) {
+	if source == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("%+v", secretSourceFields(*source))
+}
+
+// resolveCommand returns the standard output of the source's command, which
+// inherits the standard input and error for interactive prompts.
+func (source *SecretSource) resolveCommand() (secret string, err error) {
+	output := bytes.Buffer{}
+	command := exec.Command(source.Command[0], source.Command[1:]...)
+	command.Stderr = os.Stderr
+	command.Stdin = os.Stdin
+	command.Stdout = &output
+
+	err = command.Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "running secret command failed, command: '%+v'", source.Command)
+	}
+
+	return output.String(), nil
+}
+
+// resolveEnvironment returns the value of the source's environment variable.
+func (source *SecretSource) resolveEnvironment() (secret string, err error) {
+	secret, isExisting := os.LookupEnv(source.Environment)
+	if !isExisting {
+		return "", fmt.Errorf("secret environment variable is not set, name: '%+v'", source.Environment)
+	}
+
+	return secret, nil
+}
+
+// resolveFile returns the content of the source's file after checking its
+// permissions.
+func (source *SecretSource) resolveFile() (secret string, err error) {
+	info, err := os.Stat(source.File)
+	if err != nil {
+		return "", errors.Wrapf(err, "checking secret file failed, path: '%+v'", source.File)
+	} else if runtime.GOOS != "windows" &&
+		info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("secret file is accessible by the group or others, restrict it with `chmod 600`, path: '%+v', mode: '%+v'", source.File, info.Mode().Perm())
+	}
+
+	data, err := ioutil.ReadFile(source.File)
+	if err != nil {
+		return "", errors.Wrapf(err, "reading secret file failed, path: '%+v'", source.File)
+	}
+
+	return string(data), nil
+}
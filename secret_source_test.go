@@ -0,0 +1,117 @@
+package upload
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSecretSourceResolve(t *testing.T) {
+	directoryPath, err := ioutil.TempDir("", "secret-source-test")
+	if err != nil {
+		t.Fatalf("creating temporary directory failed, error: '%+v'", err)
+	}
+	defer func() { _ = os.RemoveAll(directoryPath) }()
+
+	privateFilePath := filepath.Join(directoryPath, "private")
+	writeSecretFile(t, privateFilePath, " file-secret\n", 0600)
+	sharedFilePath := filepath.Join(directoryPath, "shared")
+	writeSecretFile(t, sharedFilePath, "file-secret", 0644)
+
+	environmentName := "SLACK_EMOJI_UPLOAD_TEST_SECRET"
+	_ = os.Setenv(environmentName, "\tenvironment-secret ")
+	defer func() { _ = os.Unsetenv(environmentName) }()
+
+	testCases := []struct {
+		caseDescription string
+		expectedSecret  string
+		isValid         bool
+		source          *SecretSource
+	}{
+		{
+			caseDescription: "browser profile",
+			expectedSecret:  "d=xoxd-peanuts%2B",
+			isValid:         true,
+			source:          &SecretSource{BrowserProfile: filepath.Join("testdata", "chromium")},
+		},
+		{
+			caseDescription: "command",
+			expectedSecret:  "command-secret",
+			isValid:         true,
+			source:          &SecretSource{Command: []string{"sh", "-c", "printf '  command-secret\\n\\n'"}},
+		},
+		{
+			caseDescription: "command with empty output",
+			source:          &SecretSource{Command: []string{"sh", "-c", "printf ' \\n'"}},
+		},
+		{
+			caseDescription: "failing command",
+			source:          &SecretSource{Command: []string{"sh", "-c", "printf 'partial-secret'; exit 1"}},
+		},
+		{
+			caseDescription: "environment variable",
+			expectedSecret:  "environment-secret",
+			isValid:         true,
+			source:          &SecretSource{Environment: environmentName},
+		},
+		{
+			caseDescription: "missing environment variable",
+			source:          &SecretSource{Environment: environmentName + "_MISSING"},
+		},
+		{
+			caseDescription: "missing file",
+			source:          &SecretSource{File: filepath.Join(directoryPath, "missing")},
+		},
+		{
+			caseDescription: "multiple sources",
+			source:          &SecretSource{Environment: environmentName, File: privateFilePath},
+		},
+		{
+			caseDescription: "nil source",
+		},
+		{
+			caseDescription: "no source",
+			source:          &SecretSource{},
+		},
+		{
+			caseDescription: "private file",
+			expectedSecret:  "file-secret",
+			isValid:         true,
+			source:          &SecretSource{File: privateFilePath},
+		},
+		{
+			caseDescription: "shared file",
+			source:          &SecretSource{File: sharedFilePath},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			actualSecret, err := testCase.source.Resolve()
+			if (err == nil) != testCase.isValid {
+				t.Fatalf("secret source validity mismatches, expected: '%+v', error: '%+v'", testCase.isValid, err)
+			} else if actualSecret != testCase.expectedSecret {
+				t.Errorf("secret mismatches, expected: '%+v', actual: '%+v'", testCase.expectedSecret, actualSecret)
+			}
+		})
+	}
+}
+
+// writeSecretFile writes the secret into the file at the path with the
+// specified permissions regardless of the umask.
+func writeSecretFile(t *testing.T, path, secret string, mode os.FileMode) {
+	t.Helper()
+
+	err := ioutil.WriteFile(path, []byte(secret), mode)
+	if err != nil {
+		t.Fatalf("writing secret file failed, path: '%+v', error: '%+v'", path, err)
+	}
+
+	err = os.Chmod(path, mode)
+	if err != nil {
+		t.Fatalf("changing secret file mode failed, path: '%+v', error: '%+v'", path, err)
+	}
+}
@@ -0,0 +1,147 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gopkg.in/resty.v1"
+)
+
+const (
+	adminEmojiAddAliasPath = "api/admin.emoji.addAlias"
+	adminEmojiAddPath      = "api/admin.emoji.add"
+	adminEmojiAliasPrefix  = "alias:"
+	adminEmojiListPath     = "api/admin.emoji.list"
+	adminEmojiRemovePath   = "api/admin.emoji.remove"
+	adminEmojiRenamePath   = "api/admin.emoji.rename"
+)
+
+// adminEmoji describes a single emoji of the admin.emoji.list response.
+type adminEmoji struct {
+	DateCreated int64  `json:"date_created"`
+	UploadedBy  string `json:"uploaded_by"`
+	URL         string `json:"url"`
+}
+
+// adminEmojiListResponse describes the Slack response for the admin emoji
+// listing request.
+type adminEmojiListResponse struct {
+	Emojis           map[string]adminEmoji `json:"emoji"`
+	ResponseMetadata struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"response_metadata"`
+}
+
+// adminBackend manages the custom emojis of an Enterprise Grid organization
+// through the documented admin.emoji.* Web API methods, authorized by an
+// admin user token.
+type adminBackend struct {
+	client *Client
+}
+
+// addAlias sends the admin.emoji.addAlias request.
+func (backend *adminBackend) addAlias(ctx context.Context, aliasName, targetName string) (err error) {
+	return backend.post(ctx, fmt.Sprintf("alias addition of '%s'", aliasName), adminEmojiAddAliasPath, map[string]string{
+		"alias_for": targetName,
+		"name":      aliasName,
+	})
+}
+
+// addEmojiData fails as the admin.emoji.add method only accepts image URLs.
+func (backend *adminBackend) addEmojiData(ctx context.Context, emojiName, fileName string, data []byte) (err error) {
+	return ErrorUnsupportedOperation
+}
+
+// addEmojiURL sends the admin.emoji.add request.
+func (backend *adminBackend) addEmojiURL(ctx context.Context, emojiName, url string) (err error) {
+	return backend.post(ctx, fmt.Sprintf("emoji addition of '%s'", emojiName), adminEmojiAddPath, map[string]string{
+		"name": emojiName,
+		"url":  url,
+	})
+}
+
+// listEmojis sends the admin.emoji.list requests of every page, resolving the
+// alias URLs into alias emojis.
+func (backend *adminBackend) listEmojis(ctx context.Context) (emojis map[string]Emoji, err error) {
+	emojis = make(map[string]Emoji)
+	cursor := ""
+
+	for {
+		responseJSON := adminEmojiListResponse{}
+		_, err = backend.client.sendRequest(ctx, slackRequest{
+			description:   "admin emoji list",
+			isRateLimited: true,
+			method:        http.MethodPost,
+			newRequest: func() (request *resty.Request) {
+				return backend.client.restClient.R().SetContext(ctx).
+					SetFormData(
+						map[string]string{
+							"cursor": cursor,
+							"limit":  "1000",
+						},
+					)
+			},
+			result: &responseJSON,
+			uri:    backend.client.Host() + "/" + adminEmojiListPath,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for name, listedEmoji := range responseJSON.Emojis {
+			emoji := Emoji{
+				Created: listedEmoji.DateCreated,
+				Name:    name,
+				URL:     listedEmoji.URL,
+				UserID:  listedEmoji.UploadedBy,
+			}
+			if strings.HasPrefix(listedEmoji.URL, adminEmojiAliasPrefix) {
+				emoji.AliasFor = strings.TrimPrefix(listedEmoji.URL, adminEmojiAliasPrefix)
+				emoji.IsAlias = 1
+				emoji.URL = ""
+			}
+
+			emojis[name] = emoji
+		}
+
+		cursor = responseJSON.ResponseMetadata.NextCursor
+		if cursor == "" {
+			return emojis, nil
+		}
+	}
+}
+
+// removeEmoji sends the admin.emoji.remove request.
+func (backend *adminBackend) removeEmoji(ctx context.Context, emojiName string) (err error) {
+	return backend.post(ctx, fmt.Sprintf("emoji removal of '%s'", emojiName), adminEmojiRemovePath, map[string]string{
+		"name": emojiName,
+	})
+}
+
+// renameEmoji sends the admin.emoji.rename request.
+func (backend *adminBackend) renameEmoji(ctx context.Context, emojiName, newName string) (err error) {
+	return backend.post(ctx, fmt.Sprintf("emoji rename of '%s'", emojiName), adminEmojiRenamePath, map[string]string{
+		"name":     emojiName,
+		"new_name": newName,
+	})
+}
+
+// post sends a form request without a result other than the Slack response
+// envelope to the admin method of the specified path.
+func (backend *adminBackend) post(ctx context.Context, description, path string, formData map[string]string) (err error) {
+	_, err = backend.client.sendRequest(ctx, slackRequest{
+		description:   description,
+		isRateLimited: true,
+		method:        http.MethodPost,
+		newRequest: func() (request *resty.Request) {
+			return backend.client.restClient.R().SetContext(ctx).
+				SetFormData(formData)
+		},
+		result: &slackEnvelope{},
+		uri:    backend.client.Host() + "/" + path,
+	})
+
+	return err
+}
@@ -0,0 +1,163 @@
+package slack_test
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+	"github.com/pregnor/slack-emoji-upload/slack/slacktest"
+)
+
+func TestAdminDeleteEmoji(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("party", newPNG(t, color.White))
+	server.AddAlias("party-alias", "party")
+	client := newTestAdminClient(t, server)
+
+	err := client.DeleteEmoji("party")
+	if err != nil {
+		t.Fatalf("deleting emoji failed, error: '%+v'", err)
+	}
+
+	if count := server.RequestCount(slacktest.AdminEmojiRemovePath); count != 1 {
+		t.Errorf("admin removal request count mismatches, expected: '%+v', actual: '%+v'", 1, count)
+	} else if emojis := server.Emojis(); len(emojis) != 0 {
+		t.Errorf("deleted emoji or its alias is still served, emojis: '%+v'", emojis)
+	} else if len(client.Emojis) != 0 {
+		t.Errorf("deleted emoji or its alias is still known to the client, emojis: '%+v'", client.Emojis)
+	}
+}
+
+func TestAdminGetEmojisPaging(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	// The emojis are added after the client listed them on instantiation, so
+	// the listed pages are not rate limited.
+	client := newTestAdminClient(t, server)
+	listCount := server.RequestCount(slacktest.AdminEmojiListPath)
+
+	emojiCount := 2500
+	for index := 0; index < emojiCount; index++ {
+		server.AddEmoji(fmt.Sprintf("emoji-%04d", index), []byte("image"))
+	}
+	server.AddAlias("party-alias", "emoji-0000")
+
+	emojis, err := client.GetEmojis()
+	if err != nil {
+		t.Fatalf("listing emojis failed, error: '%+v'", err)
+	} else if len(emojis) != emojiCount+1 {
+		t.Errorf("listed emoji count mismatches, expected: '%+v', actual: '%+v'", emojiCount+1, len(emojis))
+	}
+
+	if count := server.RequestCount(slacktest.AdminEmojiListPath) - listCount; count != 3 {
+		t.Errorf("listing page count mismatches, expected: '%+v', actual: '%+v'", 3, count)
+	}
+
+	alias := emojis["party-alias"]
+	if !alias.IsAliasEmoji() ||
+		alias.AliasFor != "emoji-0000" ||
+		alias.URL != "" {
+		t.Errorf("alias is not parsed from its URL, alias: '%+v'", alias)
+	}
+
+	emoji := emojis["emoji-0000"]
+	if emoji.IsAliasEmoji() ||
+		emoji.URL != server.Emojis()["emoji-0000"].URL ||
+		emoji.UserID == "" {
+		t.Errorf("emoji is not parsed, emoji: '%+v'", emoji)
+	}
+}
+
+func TestAdminPostAlias(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("party", newPNG(t, color.White))
+	client := newTestAdminClient(t, server)
+
+	err := client.PostAlias("party-alias", "party")
+	if err != nil {
+		t.Fatalf("posting alias failed, error: '%+v'", err)
+	}
+
+	if count := server.RequestCount(slacktest.AdminEmojiAddAliasPath); count != 1 {
+		t.Errorf("admin alias request count mismatches, expected: '%+v', actual: '%+v'", 1, count)
+	} else if alias := server.Emojis()["party-alias"]; alias.AliasFor != "party" {
+		t.Errorf("alias is not added, alias: '%+v'", alias)
+	}
+
+	err = client.PostAlias("missing-alias", "missing")
+	if !errors.Is(err, slack.ErrorInvalidAlias) {
+		t.Errorf("posting alias of missing emoji returned unexpected error, expected: '%+v', actual: '%+v'", slack.ErrorInvalidAlias, err)
+	}
+}
+
+func TestAdminPostEmojiData(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	client := newTestAdminClient(t, server)
+
+	err := client.PostEmojiData("party", "party.png", newPNG(t, color.White))
+	if !errors.Is(err, slack.ErrorUnsupportedOperation) {
+		t.Errorf("posting emoji data returned unexpected error, expected: '%+v', actual: '%+v'", slack.ErrorUnsupportedOperation, err)
+	} else if count := server.RequestCount(slacktest.AdminEmojiAddPath); count != 0 {
+		t.Errorf("unsupported upload is requested, request count: '%+v'", count)
+	}
+}
+
+func TestAdminPostEmojiURL(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("party", newPNG(t, color.White))
+	client := newTestAdminClient(t, server)
+
+	err := client.PostEmojiURL("party-copy", server.Emojis()["party"].URL)
+	if err != nil {
+		t.Fatalf("posting emoji URL failed, error: '%+v'", err)
+	}
+
+	if count := server.RequestCount(slacktest.AdminEmojiAddPath); count != 1 {
+		t.Errorf("admin addition request count mismatches, expected: '%+v', actual: '%+v'", 1, count)
+	} else if image, _ := server.Image("party-copy"); !bytes.Equal(image, newPNG(t, color.White)) {
+		t.Errorf("added emoji image mismatches the image of the URL")
+	} else if _, isExisting := client.Emojis["party-copy"]; !isExisting {
+		t.Errorf("added emoji is not known to the client")
+	}
+}
+
+func TestAdminRenameEmoji(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("party", newPNG(t, color.White))
+	server.AddEmoji("taken", newPNG(t, color.Black))
+	client := newTestAdminClient(t, server)
+
+	err := client.RenameEmoji("party", "fiesta")
+	if err != nil {
+		t.Fatalf("renaming emoji failed, error: '%+v'", err)
+	}
+
+	emojis := server.Emojis()
+	if count := server.RequestCount(slacktest.AdminEmojiRenamePath); count != 1 {
+		t.Errorf("admin rename request count mismatches, expected: '%+v', actual: '%+v'", 1, count)
+	} else if _, isExisting := emojis["party"]; isExisting {
+		t.Errorf("renamed emoji is still served under its old name")
+	} else if image, _ := server.Image("fiesta"); !bytes.Equal(image, newPNG(t, color.White)) {
+		t.Errorf("renamed emoji image mismatches")
+	}
+
+	err = client.RenameEmoji("fiesta", "taken")
+	if !errors.Is(err, slack.ErrorEmojiExists) {
+		t.Errorf("renaming emoji to existing name returned unexpected error, expected: '%+v', actual: '%+v'", slack.ErrorEmojiExists, err)
+	}
+}
@@ -0,0 +1,122 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// AliasesFileName is the name of the sidecar file declaring the aliases to
+// add next to the emoji files of an upload directory.
+const AliasesFileName = "aliases.json"
+
+// NewAliasesFromFile reads the aliases declared in a JSON file mapping every
+// alias name to the name of the emoji it stands for, e.g.
+// {"thumbsup_party": "partyparrot"}.
+func NewAliasesFromFile(aliasesPath string) (aliases map[string]string, err error) {
+	if aliasesPath == "" {
+		return nil, fmt.Errorf("aliases path is empty")
+	}
+
+	aliasesData, err := ioutil.ReadFile(aliasesPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading aliases file failed, path: '%+v'", aliasesPath)
+	}
+
+	err = json.Unmarshal(aliasesData, &aliases)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling aliases JSON failed, path: '%+v'", aliasesPath)
+	}
+
+	for aliasName, targetName := range aliases {
+		if aliasName == "" ||
+			targetName == "" {
+			return nil, fmt.Errorf("alias or target name is empty, path: '%+v', alias name: '%+v', target name: '%+v'", aliasesPath, aliasName, targetName)
+		}
+	}
+
+	return aliases, nil
+}
+
+// PlanPostAliases returns the plan of adding the specified aliases by name,
+// marking the existing ones as skipped. The aliases of targets unknown to the
+// client are planned as well, as they may stand for built-in emojis.
+func (client *Client) PlanPostAliases(aliases map[string]string) (plan *Plan) {
+	return &Plan{
+		Items: client.planAliases(aliases),
+	}
+}
+
+// PostAliases adds the specified aliases by name to their target emojis,
+// falling back to the taken prefixed and suffixed names for alias names taken
+// by non-custom emojis and skipping the existing ones and the ones of targets
+// Slack does not know.
+func (client *Client) PostAliases(aliases map[string]string, emojiAliasTakenPrefix, emojiAliasTakenSuffix string) (err error) {
+	return client.PostAliasesContext(context.Background(), aliases, emojiAliasTakenPrefix, emojiAliasTakenSuffix)
+}
+
+// PostAliasesContext is PostAliases with a context cancelling its requests,
+// retries and rate limit waits.
+func (client *Client) PostAliasesContext(ctx context.Context, aliases map[string]string, emojiAliasTakenPrefix, emojiAliasTakenSuffix string) (err error) {
+	if client == nil {
+		return fmt.Errorf("client is nil")
+	} else if emojiAliasTakenSuffix == "" {
+		return fmt.Errorf("invalid empty emoji alias taken suffix")
+	}
+
+	for _, aliasName := range sortedAliasNames(aliases) {
+		targetName := aliases[aliasName]
+		takenName := emojiAliasTakenPrefix + aliasName + emojiAliasTakenSuffix
+		name, outcome, err := client.postWithTakenName(aliasName, takenName, func(name string) (err error) {
+			return client.PostAliasContext(ctx, name, targetName)
+		})
+		if errors.Is(err, ErrorInvalidAlias) {
+			log.Printf("%s: skipped alias for missing %s\n", aliasName, targetName)
+
+			continue
+		} else if err != nil {
+			return errors.Wrapf(err, "adding alias failed, name: '%+v', target name: '%+v'", aliasName, targetName)
+		}
+
+		log.Printf("%s: alias for %s %s as %s\n", aliasName, targetName, outcome, name)
+	}
+
+	return nil
+}
+
+// planAliases returns the plan items of adding the specified aliases.
+func (client *Client) planAliases(aliases map[string]string) (items []PlanItem) {
+	items = make([]PlanItem, 0, len(aliases))
+	for _, aliasName := range sortedAliasNames(aliases) {
+		item := PlanItem{
+			Action:   PlanActionAdd,
+			AliasFor: aliases[aliasName],
+			Name:     aliasName,
+		}
+
+		if _, isExisting := client.emoji(aliasName); isExisting {
+			item.Action = PlanActionSkip
+			item.Reason = "emoji already exists"
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// sortedAliasNames returns the alias names of the aliases in lexical order.
+func sortedAliasNames(aliases map[string]string) (aliasNames []string) {
+	aliasNames = make([]string, 0, len(aliases))
+	for aliasName := range aliases {
+		aliasNames = append(aliasNames, aliasName)
+	}
+	sort.Strings(aliasNames)
+
+	return aliasNames
+}
@@ -0,0 +1,97 @@
+package slack_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+	"github.com/pregnor/slack-emoji-upload/slack/slacktest"
+)
+
+func TestPlanPostAliasesBuiltInTarget(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("party", newPNG(t, color.White))
+	client := newTestClient(t, server)
+
+	plan := client.PlanPostAliases(map[string]string{
+		"party": "thumbsup",
+		"yes":   "thumbsup",
+	})
+
+	items := make(map[string]slack.PlanItem, len(plan.Items))
+	for _, item := range plan.Items {
+		items[item.Name] = item
+	}
+
+	if item := items["yes"]; item.Action != slack.PlanActionAdd {
+		t.Errorf("alias of built-in emoji is not planned, item: '%+v'", item)
+	} else if item := items["party"]; item.Action != slack.PlanActionSkip {
+		t.Errorf("existing alias name is not skipped, item: '%+v'", item)
+	}
+}
+
+func TestPostAliasBuiltInTarget(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.ReserveNames("thumbsup")
+	client := newTestClient(t, server)
+
+	err := client.PostAlias("yes", "thumbsup")
+	if err != nil {
+		t.Fatalf("adding alias of built-in emoji failed, error: '%+v'", err)
+	}
+
+	if emoji := server.Emojis()["yes"]; emoji.AliasFor != "thumbsup" {
+		t.Errorf("alias is not added, emoji: '%+v'", emoji)
+	} else if emoji := client.Emojis["yes"]; emoji.AliasFor != "thumbsup" {
+		t.Errorf("alias is not known to the client, emoji: '%+v'", emoji)
+	}
+}
+
+func TestPostAliasMissingTarget(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	client := newTestClient(t, server)
+
+	err := client.PostAlias("yes", "missing")
+	if !errors.Is(err, slack.ErrorInvalidAlias) {
+		t.Errorf("adding alias of missing emoji returned unexpected error, expected: '%+v', actual: '%+v'", slack.ErrorInvalidAlias, err)
+	}
+
+	if _, isExisting := client.Emojis["yes"]; isExisting {
+		t.Errorf("rejected alias is known to the client")
+	}
+}
+
+func TestPostAliases(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("party", newPNG(t, color.White))
+	server.ReserveNames("thumbsup")
+	client := newTestClient(t, server)
+
+	err := client.PostAliases(map[string]string{
+		"missing-alias": "missing",
+		"party-alias":   "party",
+		"yes":           "thumbsup",
+	}, "", "-2")
+	if err != nil {
+		t.Fatalf("adding aliases failed, error: '%+v'", err)
+	}
+
+	emojis := server.Emojis()
+	if emojis["party-alias"].AliasFor != "party" {
+		t.Errorf("alias of custom emoji is not added, emojis: '%+v'", emojis)
+	} else if emojis["yes"].AliasFor != "thumbsup" {
+		t.Errorf("alias of built-in emoji is not added, emojis: '%+v'", emojis)
+	} else if _, isExisting := emojis["missing-alias"]; isExisting {
+		t.Errorf("alias of missing emoji is added")
+	}
+}
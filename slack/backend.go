@@ -0,0 +1,17 @@
+package slack
+
+import (
+	"context"
+)
+
+// emojiBackend describes the Slack API a client manages the custom emojis
+// through. The backends only send the requests, the client keeps track of the
+// known emojis.
+type emojiBackend interface {
+	addAlias(ctx context.Context, aliasName, targetName string) (err error)
+	addEmojiData(ctx context.Context, emojiName, fileName string, data []byte) (err error)
+	addEmojiURL(ctx context.Context, emojiName, url string) (err error)
+	listEmojis(ctx context.Context) (emojis map[string]Emoji, err error)
+	removeEmoji(ctx context.Context, emojiName string) (err error)
+	renameEmoji(ctx context.Context, emojiName, newName string) (err error)
+}
@@ -1,144 +1,174 @@
 package slack
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
-	"net/http/httputil"
+	"net/url"
 	"os"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	backoff "github.com/cenkalti/backoff/v4"
 	"github.com/pkg/errors"
-	"golang.org/x/net/html"
 	"gopkg.in/resty.v1"
 )
 
-const (
-	apiTokenRawRegex = `.*(?:\"?api_token\"?):\s*\"([^"]+)\".*`
-)
-
-var (
-	apiTokenRegex          = regexp.MustCompile(apiTokenRawRegex)
-	errorEmojiDoesNotExist = fmt.Errorf("emoji does not exist")
-	errorEmojiExists       = fmt.Errorf("emoji already exists")
-	errorEmojiNameTaken    = fmt.Errorf("emoji name is already taken")
-)
-
 // Client provides a simple interface for interacting with the Slack API.
 type Client struct {
-	apiToken           string
-	backoffStrategy    backoff.BackOff
-	CustomizeEmojiPath string
-	EmojiAddPath       string
-	EmojiAdminListPath string
-	EmojiRemovePath    string
-	Emojis             map[string]Emoji
-	restClient         *resty.Client
-	TeamName           string
+	apiToken             string
+	apiTokenMutex        sync.RWMutex
+	apiTokenRefreshMutex sync.Mutex
+	backend              emojiBackend
+	BaseURL              string
+	ContentHashCache     *ContentHashCache
+	cookie               string
+	CustomizeEmojiPath   string
+	downloadClient       *resty.Client
+	EmojiAddPath         string
+	EmojiAdminListPath   string
+	EmojiRemovePath      string
+	Emojis               map[string]Emoji
+	emojisMutex          sync.RWMutex
+	ImageProcessor       *ImageProcessor
+	newBackoffStrategy   func() (strategy backoff.BackOff)
+	RateLimiter          *RateLimiter
+	RequestHooks         *RequestHooks
+	restClient           *resty.Client
+	TeamName             string
+	TokenCache           *TokenCache
 }
 
-// NewSlackClient instantiates a Slack client to a single team for emoji upload.
-func NewSlackClient(slackTeamName, slackCookie string) (client *Client, err error) {
-	client = &Client{
-		backoffStrategy:    backoff.NewExponentialBackOff(),
-		CustomizeEmojiPath: "customize/emoji",
-		EmojiAddPath:       "api/emoji.add",
-		EmojiAdminListPath: "api/emoji.adminList",
-		EmojiRemovePath:    "api/emoji.remove",
-		restClient: resty.NewWithClient(
-			&http.Client{
-				Timeout: 30 * time.Second,
-			},
-		).SetHeader("Cookie", slackCookie),
-		TeamName: slackTeamName,
-	}
+// CheckLogin verifies that the session cookie signs in to the team by
+// discovering the API token without retrieving the emojis, failing with
+// ErrorInvalidCookie when Slack asks for signing in again.
+func CheckLogin(slackBaseURL, slackTeamName, slackCookie string) (err error) {
+	return CheckLoginContext(context.Background(), slackBaseURL, slackTeamName, slackCookie)
+}
 
-	client.apiToken, err = client.APIToken()
+// CheckLoginContext is CheckLogin with a context cancelling its requests,
+// retries and rate limit waits.
+func CheckLoginContext(ctx context.Context, slackBaseURL, slackTeamName, slackCookie string) (err error) {
+	client, err := newSessionClient(slackBaseURL, slackTeamName, slackCookie)
 	if err != nil {
-		return nil, errors.Wrapf(err, "retrieving API token failed, client: '%+v'", client)
+		return err
 	}
 
-	client.Emojis, err = client.GetEmojis()
+	_, err = client.APITokenContext(ctx)
 	if err != nil {
-		return nil, errors.Wrapf(err, "retrieving emojis failed, client: '%+v'", client)
+		return errors.Wrapf(err, "retrieving API token failed, client: '%+v'", client)
 	}
 
-	return client, nil
+	return nil
 }
 
-// APIToken retrieves and returns the API token from an emoji customization
-// call.
-func (client *Client) APIToken() (apiToken string, err error) {
-	if client == nil {
-		return "", fmt.Errorf("client is nil")
+// NewAdminClient instantiates a Slack client managing the custom emojis of an
+// Enterprise Grid organization through the admin.emoji.* Web API methods
+// authorized by an admin user token instead of a scraped session. An empty
+// base URL targets https://slack.com.
+func NewAdminClient(slackBaseURL, adminToken string) (client *Client, err error) {
+	return NewAdminClientContext(context.Background(), slackBaseURL, adminToken)
+}
+
+// NewAdminClientContext is NewAdminClient with a context cancelling the
+// initial requests, retries and rate limit waits.
+func NewAdminClientContext(ctx context.Context, slackBaseURL, adminToken string) (client *Client, err error) {
+	if adminToken == "" {
+		return nil, fmt.Errorf("admin token is empty")
+	} else if slackBaseURL == "" {
+		slackBaseURL = "https://slack.com"
 	}
 
-	request := client.restClient.R()
-	innerError := (error)(nil)
-	response := (*resty.Response)(nil)
-	err = backoff.RetryNotifyWithTimer(
-		func() (err error) {
-			response, err = request.Get(client.CustomizeEmojiURI())
-			if err != nil {
-				requestDump, _ := httputil.DumpRequest(request.RawRequest, true)
-				innerError = errors.Wrapf(err, "request failed, request dump: '%+v'", string(requestDump))
+	client, err = newClient(slackBaseURL, DefaultAdminRateLimitTier)
+	if err != nil {
+		return nil, err
+	}
 
-				return innerError
-			}
+	client.apiToken = adminToken
+	client.backend = &adminBackend{
+		client: client,
+	}
+	client.restClient.SetAuthToken(adminToken)
 
-			if response.StatusCode() >= 400 &&
-				response.StatusCode() < 500 {
-				responseDump, _ := httputil.DumpResponse(response.RawResponse, true)
-				_ = response.RawResponse.Body.Close()
-				innerError = errors.Wrapf(err, "response contains client error, response dump: '%+v'", string(responseDump))
+	client.Emojis, err = client.GetEmojisContext(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "retrieving emojis failed, client: '%+v'", client)
+	}
 
-				return innerError
-			} else if response.StatusCode() >= 500 &&
-				response.StatusCode() < 600 {
-				responseDump, _ := httputil.DumpResponse(response.RawResponse, true)
-				_ = response.RawResponse.Body.Close()
-				innerError = errors.Wrapf(err, "response contains server error, response dump: '%+v'", string(responseDump))
+	return client, nil
+}
 
-				return innerError
-			}
+// NewSlackClient instantiates a Slack client to a single team for emoji upload.
+func NewSlackClient(slackTeamName, slackCookie string) (client *Client, err error) {
+	return NewSlackClientContext(context.Background(), slackTeamName, slackCookie)
+}
 
-			return nil
-		},
-		client.backoffStrategy,
-		func(err error, backoffDelay time.Duration) {
-			log.Printf("requesting API token temporarily failed and will be retried, error: '%+v', backoff delay: '%+v'\n", err, backoffDelay)
-		},
-		nil,
-	)
+// NewSlackClientContext is NewSlackClient with a context cancelling the initial
+// requests, retries and rate limit waits.
+func NewSlackClientContext(ctx context.Context, slackTeamName, slackCookie string) (client *Client, err error) {
+	return NewSlackClientWithOptionsContext(ctx, slackTeamName, slackCookie, ClientOptions{})
+}
 
-	defer func() { _ = response.RawResponse.Body.Close() }()
+// NewSlackClientWithOptions is NewSlackClient with the specified optional
+// behaviour, e.g. another base URL or a token cache.
+func NewSlackClientWithOptions(slackTeamName, slackCookie string, options ClientOptions) (client *Client, err error) {
+	return NewSlackClientWithOptionsContext(context.Background(), slackTeamName, slackCookie, options)
+}
 
+// NewSlackClientWithOptionsContext is NewSlackClientWithOptions with a
+// context cancelling the initial requests, retries and rate limit waits.
+func NewSlackClientWithOptionsContext(ctx context.Context, slackTeamName, slackCookie string, options ClientOptions) (client *Client, err error) {
+	client, err = newSessionClient(options.BaseURL, slackTeamName, slackCookie)
 	if err != nil {
-		return "", innerError
+		return nil, err
 	}
 
-	bodyReader := bytes.NewReader(response.Body())
-	document, err := html.Parse(bodyReader)
+	client.TokenCache = options.TokenCache
+
+	err = client.setAPIToken(ctx)
 	if err != nil {
-		innerError = errors.Wrapf(err, "parsing API token body response failed, raw body: '%+v'", string(response.Body()))
+		return nil, errors.Wrapf(err, "retrieving API token failed, client: '%+v'", client)
+	}
 
-		return "", innerError
+	client.Emojis, err = client.GetEmojisContext(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "retrieving emojis failed, client: '%+v'", client)
 	}
 
-	apiToken = apiTokenFromHTMLRecursively(document)
-	if apiToken == "" {
-		innerError = fmt.Errorf("API token not found, raw document: '%+v'", string(response.Body()))
+	return client, nil
+}
 
-		return "", innerError
+// APIToken discovers and returns the API token from the emoji customization
+// page, failing with ErrorInvalidCookie when Slack asks for signing in again.
+func (client *Client) APIToken() (apiToken string, err error) {
+	return client.APITokenContext(context.Background())
+}
+
+// APITokenContext is APIToken with a context cancelling its requests, retries
+// and rate limit waits.
+func (client *Client) APITokenContext(ctx context.Context) (apiToken string, err error) {
+	if client == nil {
+		return "", fmt.Errorf("client is nil")
 	}
 
-	return apiToken, nil
+	response, err := client.sendRequest(ctx, slackRequest{
+		description:   "API token",
+		isRateLimited: true,
+		method:        http.MethodGet,
+		newRequest: func() (request *resty.Request) {
+			return client.restClient.R().SetContext(ctx)
+		},
+		uri: client.CustomizeEmojiURI(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return client.apiTokenFromResponse(response)
 }
 
 // CustomizeEmojiURI returns the URI of the customize/emoji endpoint.
@@ -151,104 +181,85 @@ func (client *Client) CustomizeEmojiURI() (uri string) {
 }
 
 // DeleteEmoji deletes a single emoji identified by its name from the connected
-// Slack team's custom emojis.
+// Slack team's custom emojis, which removes its aliases as well.
 func (client *Client) DeleteEmoji(emojiName string) (err error) {
+	return client.DeleteEmojiContext(context.Background(), emojiName)
+}
+
+// DeleteEmojiContext is DeleteEmoji with a context cancelling its requests,
+// retries and rate limit waits.
+func (client *Client) DeleteEmojiContext(ctx context.Context, emojiName string) (err error) {
 	if client == nil {
 		return fmt.Errorf("client is nil")
 	}
 
-	if _, isExisting := client.Emojis[emojiName]; !isExisting {
-		return errorEmojiDoesNotExist
+	emoji, isExisting := client.emoji(emojiName)
+	if !isExisting {
+		return ErrorEmojiDoesNotExist
 	}
 
-	innerError := (error)(nil)
-	isAssertable := false
-	isSuccessful := false
-	request := client.restClient.R().
-		SetFormData(
-			map[string]string{
-				"name":  emojiName,
-				"token": client.apiToken,
-			},
-		)
-	response := (*resty.Response)(nil)
-	responseJSON := make(map[string]interface{})
-
-	err = backoff.RetryNotifyWithTimer(
-		func() (err error) {
-			response, err = request.Post(client.EmojiRemoveURI())
-			if err != nil {
-				requestDump, _ := httputil.DumpRequest(request.RawRequest, true)
-				innerError = errors.Wrapf(err, "request failed, request dump: '%+v'", string(requestDump))
-
-				return innerError
-			}
-
-			defer func() { _ = response.RawResponse.Body.Close() }()
-
-			if response.StatusCode() >= 400 &&
-				response.StatusCode() < 500 {
-				responseDump, _ := httputil.DumpResponse(response.RawResponse, true)
-				innerError = errors.Wrapf(err, "response contains client error, response dump: '%+v'", string(responseDump))
-
-				return innerError
-			} else if response.StatusCode() >= 500 &&
-				response.StatusCode() < 600 {
-				responseDump, _ := httputil.DumpResponse(response.RawResponse, true)
-				innerError = errors.Wrapf(err, "response contains server error, response dump: '%+v'", string(responseDump))
-
-				return innerError
-			}
-
-			err = json.Unmarshal(response.Body(), &responseJSON)
-			if err != nil {
-				innerError = errors.Wrapf(err, "unmarshalling JSON response failed, raw JSON response: '%+v'", string(response.Body()))
-
-				return innerError
-			}
-
-			isSuccessful, isAssertable = responseJSON["ok"].(bool)
-			if !isAssertable {
-				innerError = fmt.Errorf("response OK flag could not be asserted to boolean, raw response JSON: '%+v'", string(response.Body()))
-
-				return innerError
-			}
-
-			if !isSuccessful {
-				innerError = fmt.Errorf("response contained not OK status, response: '%+v'", responseJSON)
-
-				return innerError
-			}
-
-			return nil
-		},
-		client.backoffStrategy,
-		func(err error, backoffDelay time.Duration) {
-			log.Printf("requesting emoji removal temporarily failed and will be retried, name: '%+v', error: '%+v', backoff delay: '%+v'\n", emojiName, err, backoffDelay)
-		},
-		nil,
-	)
+	err = client.backend.removeEmoji(ctx, emojiName)
 	if err != nil {
-		return innerError
+		return err
+	}
+
+	client.emojisMutex.Lock()
+	delete(client.Emojis, emojiName)
+	for name, alias := range client.Emojis {
+		if alias.AliasFor == emojiName {
+			delete(client.Emojis, name)
+		}
 	}
+	client.emojisMutex.Unlock()
+
+	client.ContentHashCache.deleteHash(emoji.URL)
 
 	return nil
 }
 
 // DeleteEmojis deletes all custom emojis from the connected Slack team.
 func (client *Client) DeleteEmojis() (err error) {
+	return client.DeleteEmojisContext(context.Background())
+}
+
+// DeleteEmojisContext is DeleteEmojis with a context cancelling its requests,
+// retries and rate limit waits.
+func (client *Client) DeleteEmojisContext(ctx context.Context) (err error) {
+	return client.DeleteEmojisWithOptionsContext(ctx, DeleteOptions{})
+}
+
+// DeleteEmojisWithOptions is DeleteEmojis writing the deletion plan instead
+// on dry runs.
+func (client *Client) DeleteEmojisWithOptions(options DeleteOptions) (err error) {
+	return client.DeleteEmojisWithOptionsContext(context.Background(), options)
+}
+
+// DeleteEmojisWithOptionsContext is DeleteEmojisWithOptions with a context
+// cancelling its requests, retries and rate limit waits.
+func (client *Client) DeleteEmojisWithOptionsContext(ctx context.Context, options DeleteOptions) (err error) {
 	if client == nil {
 		return fmt.Errorf("client is nil")
 	}
 
-	totalCount := len(client.Emojis)
+	if options.DryRun != nil {
+		err = options.DryRun.Write(client.PlanDeleteEmojis())
+		if err != nil {
+			return errors.Wrap(err, "writing deletion plan failed")
+		}
+
+		return nil
+	}
+
+	names := client.emojiNames()
+	totalCount := len(names)
 	deleteCount := 0
-	for name := range client.Emojis {
+	for _, name := range names {
 		log.Printf("%s\n", name)
 
-		err = client.DeleteEmoji(name)
+		err = client.DeleteEmojiContext(ctx, name)
 		if err != nil &&
-			err != errorEmojiDoesNotExist {
+			!errors.Is(err, ErrorEmojiDoesNotExist) &&
+			!errors.Is(err, ErrorEmojiNotFound) {
 			return errors.Wrapf(err, "deleting emoji failed, name: '%+v'", name)
 		} else if err == nil {
 			log.Printf("deleted\n")
@@ -290,351 +301,591 @@ func (client *Client) EmojiRemoveURI() (uri string) {
 
 // GetEmojis returns the available custom emojis by name in a Slack team.
 func (client *Client) GetEmojis() (emojis map[string]Emoji, err error) {
+	return client.GetEmojisContext(context.Background())
+}
+
+// GetEmojisContext is GetEmojis with a context cancelling its requests, retries
+// and rate limit waits.
+func (client *Client) GetEmojisContext(ctx context.Context) (emojis map[string]Emoji, err error) {
 	if client == nil {
 		return nil, fmt.Errorf("client is nil")
 	}
 
-	emojis = make(map[string]Emoji)
-	innerError := (error)(nil)
-	page := 1
-	pageCount := 2
-	pageSize := 1000
-	request := client.restClient.R().
-		SetFormData(
-			map[string]string{
-				"count": fmt.Sprintf("%d", pageSize),
-				"page":  fmt.Sprintf("%d", page),
-				"query": "",
-				"token": client.apiToken,
-			},
-		)
-	response := (*resty.Response)(nil)
-	responseJSON := EmojiListResponse{}
+	return client.backend.listEmojis(ctx)
+}
 
-	for page <= pageCount {
-		request.FormData["page"][0] = fmt.Sprintf("%d", page)
-		request.FormData["count"][0] = fmt.Sprintf("%d", pageSize)
+// Host returns the Slack host URL for the configured team or the configured
+// base URL when it is set.
+func (client *Client) Host() (host string) {
+	if client == nil {
+		return host
+	} else if client.BaseURL != "" {
+		return client.BaseURL
+	}
 
-		err = backoff.RetryNotifyWithTimer(
-			func() (err error) {
-				response, err = request.Post(client.EmojiAdminListURI())
-				if err != nil {
-					requestDump, _ := httputil.DumpRequest(request.RawRequest, true)
+	return fmt.Sprintf("https://%s.slack.com", client.TeamName)
+}
 
-					return errors.Wrapf(err, "request failed, request dump: '%+v'", string(requestDump))
-				}
+// PlanDeleteEmojis returns the plan of deleting all custom emojis from the
+// connected Slack team.
+func (client *Client) PlanDeleteEmojis() (plan *Plan) {
+	plan = &Plan{
+		Items: make([]PlanItem, 0),
+	}
+	if client == nil {
+		return plan
+	}
 
-				defer func() { _ = response.RawResponse.Body.Close() }()
+	for _, name := range client.emojiNames() {
+		plan.Items = append(plan.Items, PlanItem{
+			Action: PlanActionDelete,
+			Name:   name,
+		})
+	}
 
-				if response.StatusCode() >= 400 &&
-					response.StatusCode() < 500 {
-					responseDump, _ := httputil.DumpResponse(response.RawResponse, true)
-					innerError = errors.Wrapf(err, "response contains client error, response dump: '%+v'", string(responseDump))
+	return plan
+}
 
-					return innerError
-				} else if response.StatusCode() >= 500 &&
-					response.StatusCode() < 600 {
-					responseDump, _ := httputil.DumpResponse(response.RawResponse, true)
-					innerError = errors.Wrapf(err, "response contains server error, response dump: '%+v'", string(responseDump))
+// PlanPostEmojis returns the plan of uploading all emojis in the specified
+// directory, mapping every file to its prefixed and suffixed emoji name and
+// marking invalid files and the files of existing, duplicate or journaled
+// names as skipped or, when replacing changed emojis, existing names with a
+// differing image as replaced, followed by the aliases declared in the
+// directory's aliases sidecar file.
+func (client *Client) PlanPostEmojis(emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix string, options UploadOptions) (plan *Plan, err error) {
+	return client.PlanPostEmojisContext(context.Background(), emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix, options)
+}
 
-					return innerError
-				}
+// PlanPostEmojisContext is PlanPostEmojis with a context cancelling its
+// requests, retries and rate limit waits.
+func (client *Client) PlanPostEmojisContext(ctx context.Context, emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix string, options UploadOptions) (plan *Plan, err error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is nil")
+	} else if emojiDirectoryPath == "" {
+		return nil, fmt.Errorf("invalid empty emoji directory path")
+	}
 
-				err = json.Unmarshal(response.Body(), &responseJSON)
-				if err != nil {
-					innerError = errors.Wrapf(err, "unmarshalling JSON response failed, raw JSON response: '%+v'", string(response.Body()))
+	paths, err := emojiFilePaths(emojiDirectoryPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "iterating emoji directory failed, emoji directory path: '%+v'", emojiDirectoryPath)
+	}
 
-					return innerError
-				}
+	plan = &Plan{
+		Items: make([]PlanItem, 0, len(paths)),
+	}
+	plannedPaths := make(map[string]string, len(paths))
+	for _, path := range paths {
+		name, takenName := newEmojiNameFromFilePath(path, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix)
+		item := PlanItem{
+			Action:    PlanActionAdd,
+			Name:      name,
+			Path:      path,
+			TakenName: takenName,
+		}
 
-				if !responseJSON.IsOk {
-					innerError = fmt.Errorf("not OK response received, response: '%+v'", responseJSON)
+		validationError := client.validateEmojiFile(path, options.ImageConstraints)
+		if !errors.Is(validationError, ErrorInvalidEmojiImage) &&
+			validationError != nil {
+			return nil, errors.Wrapf(validationError, "validating emoji file failed, path: '%+v'", path)
+		}
 
-					return innerError
-				}
+		if validationError != nil {
+			item.Action = PlanActionSkip
+			item.Reason = validationError.Error()
+		} else if remoteEmoji, isExisting := client.emoji(name); isExisting {
+			item.Action = PlanActionSkip
+			item.Reason = "emoji already exists"
 
-				return nil
-			},
-			client.backoffStrategy,
-			func(err error, backoffDelay time.Duration) {
-				log.Printf("requesting emoji list temporarily failed and will be retried, error: '%+v', backoff delay: '%+v'\n", err, backoffDelay)
-			},
-			nil,
-		)
-		if err != nil {
-			return nil, innerError
+			if options.IsReplacingChanged &&
+				!remoteEmoji.IsAliasEmoji() {
+				isChanged, err := client.isEmojiChanged(ctx, remoteEmoji, path)
+				if err != nil {
+					return nil, errors.Wrapf(err, "comparing emoji content failed, name: '%+v', path: '%+v'", name, path)
+				} else if isChanged {
+					item.Action = PlanActionReplace
+					item.Reason = "image content differs"
+				}
+			}
+		} else if plannedPath, isPlanned := plannedPaths[name]; isPlanned {
+			item.Action = PlanActionSkip
+			item.Reason = "name is already planned for " + plannedPath
+		} else if options.Journal.IsCompleted(path) {
+			item.Action = PlanActionSkip
+			item.Reason = "journaled as completed"
+		} else {
+			plannedPaths[name] = path
 		}
 
-		for _, emoji := range responseJSON.Emojis {
-			emojis[emoji.Name] = emoji
+		if item.Action != PlanActionAdd {
+			item.TakenName = ""
 		}
 
-		pageCount = responseJSON.Paging.PageCount
-		page = responseJSON.Paging.Page + 1
+		plan.Items = append(plan.Items, item)
 	}
 
-	return emojis, nil
+	aliases, err := directoryAliases(emojiDirectoryPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading directory aliases failed, emoji directory path: '%+v'", emojiDirectoryPath)
+	}
+
+	plan.Items = append(plan.Items, client.planAliases(aliases)...)
+
+	return plan, nil
 }
 
-// Host returns the Slack host URL for the configured team.
-func (client *Client) Host() (host string) {
+// PostAlias adds an alias under the given name to an existing custom or
+// built-in emoji. The target is not checked against the known custom emojis,
+// as built-in emojis are unknown to the client, Slack rejects missing targets
+// with ErrorInvalidAlias.
+func (client *Client) PostAlias(aliasName, targetName string) (err error) {
+	return client.PostAliasContext(context.Background(), aliasName, targetName)
+}
+
+// PostAliasContext is PostAlias with a context cancelling its requests, retries
+// and rate limit waits.
+func (client *Client) PostAliasContext(ctx context.Context, aliasName, targetName string) (err error) {
 	if client == nil {
-		return host
+		return fmt.Errorf("client is nil")
 	}
 
-	return fmt.Sprintf("https://%s.slack.com", client.TeamName)
+	if _, isExisting := client.emoji(aliasName); isExisting {
+		return ErrorEmojiExists
+	}
+
+	err = client.backend.addAlias(ctx, aliasName, targetName)
+	if err != nil {
+		return err
+	}
+
+	client.setEmoji(Emoji{
+		AliasFor: targetName,
+		IsAlias:  1,
+		Name:     aliasName,
+	})
+
+	return nil
 }
 
-// PostEmoji uploads an emoji file specified with its path under the given name.
+// PostEmoji uploads an emoji file specified with its path under the given
+// name, processing its image first when the client has an image processor.
 func (client *Client) PostEmoji(emojiName, emojiPath string) (err error) {
+	return client.PostEmojiContext(context.Background(), emojiName, emojiPath)
+}
+
+// PostEmojiContext is PostEmoji with a context cancelling its requests, retries
+// and rate limit waits.
+func (client *Client) PostEmojiContext(ctx context.Context, emojiName, emojiPath string) (err error) {
 	if client == nil {
 		return fmt.Errorf("client is nil")
 	}
 
-	if _, isExisting := client.Emojis[emojiName]; isExisting {
-		return errorEmojiExists
+	if _, isExisting := client.emoji(emojiName); isExisting {
+		return ErrorEmojiExists
 	}
 
-	innerError := (error)(nil)
-	isAssertable := false
-	isSuccessful := false
-	request := client.restClient.R().
-		SetFormData(
-			map[string]string{
-				"mode":  "data",
-				"name":  emojiName,
-				"token": client.apiToken,
-			},
-		).
-		SetFile("image", emojiPath)
-	response := (*resty.Response)(nil)
-	responseJSON := make(map[string]interface{})
-
-	err = backoff.RetryNotifyWithTimer(
-		func() (err error) {
-			response, err = request.Post(client.EmojiAddURI())
-			if err != nil {
-				requestDump, _ := httputil.DumpRequest(request.RawRequest, true)
-				innerError = errors.Wrapf(err, "request failed, request dump: '%+v'", string(requestDump))
-
-				return innerError
-			}
+	data, err := client.emojiFileData(emojiPath)
+	if err != nil {
+		return errors.Wrapf(err, "preparing emoji file failed, path: '%+v'", emojiPath)
+	}
 
-			defer func() { _ = response.RawResponse.Body.Close() }()
+	return client.PostEmojiDataContext(ctx, emojiName, filepath.Base(emojiPath), data)
+}
 
-			if response.StatusCode() == 429 {
-				_ = response.RawResponse.Body.Close()
-				retrySeconds := response.Header().Get("Retry-After")
-				retryDuration, err := time.ParseDuration(retrySeconds + "s")
-				if err != nil {
-					innerError = errors.Wrapf(err, "parsing retry duration failed, raw retry seconds: '%+v'", retrySeconds)
+// PostEmojiData uploads emoji image data under the given name, sending it as
+// a file of the specified name.
+func (client *Client) PostEmojiData(emojiName, fileName string, data []byte) (err error) {
+	return client.PostEmojiDataContext(context.Background(), emojiName, fileName, data)
+}
+
+// PostEmojiDataContext is PostEmojiData with a context cancelling its requests,
+// retries and rate limit waits.
+func (client *Client) PostEmojiDataContext(ctx context.Context, emojiName, fileName string, data []byte) (err error) {
+	if client == nil {
+		return fmt.Errorf("client is nil")
+	}
+
+	if _, isExisting := client.emoji(emojiName); isExisting {
+		return ErrorEmojiExists
+	}
+
+	err = client.backend.addEmojiData(ctx, emojiName, fileName, data)
+	if err != nil {
+		return err
+	}
+
+	client.setEmoji(Emoji{
+		Name: emojiName,
+	})
 
-					return innerError
+	return nil
+}
+
+// PostEmojis uploads all emojis in the specified directory using the file's
+// name without extension as the emoji name prefixed and suffixed with the
+// specified qualifiers. The aliases declared in the directory's aliases
+// sidecar file are added after the emoji files.
+func (client *Client) PostEmojis(emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix string) (err error) {
+	return client.PostEmojisContext(context.Background(), emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix)
+}
+
+// PostEmojisContext is PostEmojis with a context cancelling its requests,
+// retries and rate limit waits.
+func (client *Client) PostEmojisContext(ctx context.Context, emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix string) (err error) {
+	return client.PostEmojisWithOptionsContext(ctx, emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix, UploadOptions{})
+}
+
+// PostEmojisWithOptions is PostEmojis optionally uploading multiple emojis in
+// parallel, skipping the files violating the image constraints, replacing the
+// existing emojis with a changed image, continuing after failed files or
+// writing the upload plan on dry runs.
+func (client *Client) PostEmojisWithOptions(emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix string, options UploadOptions) (err error) {
+	return client.PostEmojisWithOptionsContext(context.Background(), emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix, options)
+}
+
+// PostEmojisWithOptionsContext is PostEmojisWithOptions with a context
+// cancelling its requests, retries and rate limit waits.
+func (client *Client) PostEmojisWithOptionsContext(ctx context.Context, emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix string, options UploadOptions) (err error) {
+	if client == nil {
+		return fmt.Errorf("client is nil")
+	} else if emojiDirectoryPath == "" {
+		return fmt.Errorf("invalid empty emoji directory path")
+	} else if emojiAliasTakenSuffix == "" {
+		return fmt.Errorf("invalid empty emoji alias taken suffix")
+	}
+
+	if options.DryRun != nil {
+		plan, err := client.PlanPostEmojisContext(ctx, emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix, options)
+		if err != nil {
+			return errors.Wrapf(err, "planning emoji uploads failed, emoji directory path: '%+v'", emojiDirectoryPath)
+		}
+
+		err = options.DryRun.Write(plan)
+		if err != nil {
+			return errors.Wrapf(err, "writing upload plan failed, emoji directory path: '%+v'", emojiDirectoryPath)
+		}
+
+		return nil
+	}
+
+	paths, err := emojiFilePaths(emojiDirectoryPath)
+	if err != nil {
+		return errors.Wrapf(err, "iterating emoji directory failed, emoji directory path: '%+v'", emojiDirectoryPath)
+	}
+
+	concurrency := options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	progress := &uploadProgress{
+		totalCount: len(paths),
+	}
+	pathChannel := make(chan string)
+	errorChannel := make(chan error, concurrency)
+	waitGroup := sync.WaitGroup{}
+	for workerIndex := 0; workerIndex < concurrency; workerIndex++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+
+			err := (error)(nil)
+
+			for path := range pathChannel {
+				if ctx.Err() != nil {
+					errorChannel <- errors.Wrap(ctx.Err(), "uploading emojis cancelled")
+
+					return
 				}
 
-				log.Printf("waiting rate limit for %s\n", retryDuration)
-				<-time.After(retryDuration)
+				baseName := filepath.Base(path)
+				if options.Journal.IsCompleted(path) {
+					entry, _ := options.Journal.Entry(path)
+					entry.Outcome = UploadOutcomeSkipped
+					options.Report.record(entry)
+					log.Printf("%s: skipped journaled\n%s\n\n", baseName, progress.skip())
+
+					continue
+				}
+
+				name, takenName := newEmojiNameFromFilePath(path, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix)
+
+				entry := JournalEntry{
+					Name: name,
+					Path: path,
+				}
+
+				err = client.validateEmojiFile(path, options.ImageConstraints)
+				if errors.Is(err, ErrorInvalidEmojiImage) {
+					entry.Outcome = UploadOutcomeInvalid
+				} else if err != nil {
+					err = errors.Wrapf(err, "validating emoji file failed, path: '%+v'", path)
+				} else {
+					entry.Name, entry.Outcome, err = client.postWithTakenName(name, takenName, func(name string) (err error) {
+						return client.PostEmojiContext(ctx, name, path)
+					})
+					if err == nil &&
+						entry.Outcome == UploadOutcomeSkipped &&
+						options.IsReplacingChanged {
+						isReplaced := false
+						isReplaced, err = client.replaceChangedEmoji(ctx, entry.Name, path)
+						if isReplaced {
+							entry.Outcome = UploadOutcomeReplaced
+						}
+					}
+				}
 
-				response, err = request.Post(client.EmojiAddURI())
 				if err != nil {
-					requestDump, _ := httputil.DumpRequest(request.RawRequest, true)
-					innerError = errors.Wrapf(err, "rate limited request failed, request dump: '%+v'", string(requestDump))
+					entry.Error = err.Error()
 
-					return innerError
+					if entry.Outcome != UploadOutcomeInvalid {
+						entry.Outcome = UploadOutcomeFailed
+					}
 				}
 
-				if response.StatusCode() >= 400 &&
-					response.StatusCode() < 500 {
-					responseDump, _ := httputil.DumpResponse(response.RawResponse, true)
-					innerError = errors.Wrapf(err, "rate limited response contains client error, response dump: '%+v'", string(responseDump))
+				entry.Time = time.Now().UTC()
+				journalError := options.Journal.Record(entry)
+				if journalError != nil {
+					errorChannel <- errors.Wrapf(journalError, "recording journal entry failed, entry: '%+v'", entry)
 
-					return innerError
-				} else if response.StatusCode() >= 500 &&
-					response.StatusCode() < 600 {
-					responseDump, _ := httputil.DumpResponse(response.RawResponse, true)
-					innerError = errors.Wrapf(err, "rate limited response contains server error, response dump: '%+v'", string(responseDump))
+					return
+				}
+
+				options.Report.record(entry)
+
+				if entry.Outcome == UploadOutcomeFailed &&
+					(!options.IsContinuingOnError ||
+						ctx.Err() != nil) {
+					errorChannel <- errors.Wrapf(err, "uploading emoji failed, path: '%+v'", path)
 
-					return innerError
+					return
+				}
+
+				switch entry.Outcome {
+				case UploadOutcomeFailed:
+					log.Printf("%s: failed, error: %s\n%s\n\n", baseName, entry.Error, progress.fail())
+				case UploadOutcomeInvalid:
+					log.Printf("%s: skipped invalid, reason: %s\n%s\n\n", baseName, entry.Error, progress.skip())
+				case UploadOutcomeNameTaken:
+					log.Printf("%s: uploaded as taken name %s\n%s\n\n", baseName, entry.Name, progress.upload())
+				case UploadOutcomeReplaced:
+					log.Printf("%s: replaced changed %s\n%s\n\n", baseName, entry.Name, progress.upload())
+				case UploadOutcomeSkipped:
+					log.Printf("%s: skipped existing %s\n%s\n\n", baseName, entry.Name, progress.skip())
+				case UploadOutcomeUploaded:
+					log.Printf("%s: uploaded as %s\n%s\n\n", baseName, entry.Name, progress.upload())
 				}
-			} else if response.StatusCode() >= 400 &&
-				response.StatusCode() < 500 {
-				responseDump, _ := httputil.DumpResponse(response.RawResponse, true)
-				innerError = errors.Wrapf(err, "response contains client error, response dump: '%+v'", string(responseDump))
-
-				return innerError
-			} else if response.StatusCode() >= 500 &&
-				response.StatusCode() < 600 {
-				responseDump, _ := httputil.DumpResponse(response.RawResponse, true)
-				innerError = errors.Wrapf(err, "response contains server error, response dump: '%+v'", string(responseDump))
-
-				return innerError
 			}
+		}()
+	}
 
-			err = json.Unmarshal(response.Body(), &responseJSON)
-			if err != nil {
-				innerError = errors.Wrapf(err, "unmarshalling JSON response failed, raw JSON response: '%+v'", string(response.Body()))
+feeding:
+	for _, path := range paths {
+		select {
+		case pathChannel <- path:
+		case err = <-errorChannel:
+			break feeding
+		case <-ctx.Done():
+			err = errors.Wrap(ctx.Err(), "uploading emojis cancelled")
 
-				return innerError
-			}
+			break feeding
+		}
+	}
+	close(pathChannel)
+	waitGroup.Wait()
+	close(errorChannel)
 
-			isSuccessful, isAssertable = responseJSON["ok"].(bool)
-			if !isAssertable {
-				innerError = fmt.Errorf("response OK flag could not be asserted to boolean, raw response JSON: '%+v'", string(response.Body()))
+	if err == nil {
+		err = <-errorChannel
+	}
 
-				return innerError
-			}
+	if err != nil {
+		return errors.Wrapf(err, "iterating emoji directory for uploading failed, emoji directory path: '%+v'", emojiDirectoryPath)
+	}
 
-			if !isSuccessful {
-				errorString, isAssertable := responseJSON["error"].(string)
-				if isAssertable &&
-					(errorString == "error_name_taken" ||
-						errorString == "error_name_taken_i18n") {
-					innerError = errorEmojiNameTaken
+	aliases, err := directoryAliases(emojiDirectoryPath)
+	if err != nil {
+		return errors.Wrapf(err, "loading directory aliases failed, emoji directory path: '%+v'", emojiDirectoryPath)
+	}
 
-					return backoff.Permanent(innerError)
-				}
+	err = client.PostAliasesContext(ctx, aliases, emojiAliasTakenPrefix, emojiAliasTakenSuffix)
+	if err != nil {
+		return errors.Wrapf(err, "adding directory aliases failed, emoji directory path: '%+v'", emojiDirectoryPath)
+	}
 
-				innerError = fmt.Errorf("retrying request, failed response: '%+v'", responseJSON)
+	if progress.failCount != 0 {
+		return fmt.Errorf("uploading emoji files failed, failed count: '%+v', emoji directory path: '%+v'", progress.failCount, emojiDirectoryPath)
+	}
 
-				return innerError
-			}
+	return nil
+}
 
-			return nil
-		},
-		client.backoffStrategy,
-		func(err error, backoffDelay time.Duration) {
-			log.Printf("requesting emoji addition temporarily failed and will be retried, error: '%+v', backoff delay: '%+v'\n", err, backoffDelay)
-		},
-		nil,
-	)
+// PostEmojiURL adds an emoji under the given name from the image at the
+// specified URL. The admin API fetches the image itself, while the session
+// API only accepts image data, so session clients download the image into
+// memory first, failing with ErrorInvalidEmojiImage without reading the rest
+// of it when it exceeds the maximum file size of DefaultImageConstraints.
+func (client *Client) PostEmojiURL(emojiName, url string) (err error) {
+	return client.PostEmojiURLContext(context.Background(), emojiName, url)
+}
+
+// PostEmojiURLContext is PostEmojiURL with a context cancelling its requests,
+// retries and rate limit waits.
+func (client *Client) PostEmojiURLContext(ctx context.Context, emojiName, url string) (err error) {
+	if client == nil {
+		return fmt.Errorf("client is nil")
+	}
+
+	if _, isExisting := client.emoji(emojiName); isExisting {
+		return ErrorEmojiExists
+	}
+
+	err = client.backend.addEmojiURL(ctx, emojiName, url)
 	if err != nil {
-		return innerError
+		return err
 	}
 
+	client.setEmoji(Emoji{
+		Name: emojiName,
+	})
+
 	return nil
 }
 
-// PostEmojis uploads all emojis in the specified directory using the file's
-// name without extension as the emoji name prefixed and suffixed with the
-// specified qualifiers.
-func (client *Client) PostEmojis(emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix string) (err error) {
+// RenameEmoji renames an existing emoji, moving its aliases along, which only
+// the admin API supports.
+func (client *Client) RenameEmoji(emojiName, newName string) (err error) {
+	return client.RenameEmojiContext(context.Background(), emojiName, newName)
+}
+
+// RenameEmojiContext is RenameEmoji with a context cancelling its requests,
+// retries and rate limit waits.
+func (client *Client) RenameEmojiContext(ctx context.Context, emojiName, newName string) (err error) {
 	if client == nil {
 		return fmt.Errorf("client is nil")
-	} else if emojiDirectoryPath == "" {
-		return fmt.Errorf("invalid empty emoji directory path")
-	} else if emojiAliasTakenSuffix == "" {
-		return fmt.Errorf("invalid empty emoji alias taken suffix")
 	}
 
-	skipCount := 0
-	totalCount := 0
-	uploadCount := 0
+	emoji, isExisting := client.emoji(emojiName)
+	if !isExisting {
+		return ErrorEmojiDoesNotExist
+	} else if _, isExisting = client.emoji(newName); isExisting {
+		return ErrorEmojiExists
+	}
 
-	err = filepath.Walk(emojiDirectoryPath, func(path string, info os.FileInfo, itemError error) (walkError error) {
-		if itemError != nil {
-			return errors.Wrapf(err, "walking path failed, path: '%+v', info: '%+v'", path, info)
-		} else if info.IsDir() {
-			return nil
+	err = client.backend.renameEmoji(ctx, emojiName, newName)
+	if err != nil {
+		return err
+	}
+
+	client.emojisMutex.Lock()
+	delete(client.Emojis, emojiName)
+	for name, alias := range client.Emojis {
+		if alias.AliasFor == emojiName {
+			alias.AliasFor = newName
+			client.Emojis[name] = alias
 		}
+	}
+	client.emojisMutex.Unlock()
 
-		totalCount++
+	emoji.Name = newName
+	client.setEmoji(emoji)
 
-		return nil
-	})
-	if err != nil {
-		return errors.Wrapf(err, "iterating emoji directory for counting failed, emoji directory path: '%+v'", emojiDirectoryPath)
+	return nil
+}
+
+// String returns the human readable form of the client with its cookie and
+// API token redacted.
+func (client *Client) String() (text string) {
+	if client == nil {
+		return "<nil>"
 	}
 
+	return fmt.Sprintf(
+		"{BaseURL:%s EmojiCount:%d TeamName:%s apiToken:%s cookie:%s}",
+		client.BaseURL,
+		len(client.emojiNames()),
+		client.TeamName,
+		Redact(client.currentAPIToken(), client.currentAPIToken()),
+		Redact(client.cookie, client.cookie),
+	)
+}
+
+// directoryAliases returns the aliases declared in the aliases sidecar file
+// of the emoji directory if there is one.
+func directoryAliases(emojiDirectoryPath string) (aliases map[string]string, err error) {
+	aliasesPath := filepath.Join(emojiDirectoryPath, AliasesFileName)
+	if _, err = os.Stat(aliasesPath); os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+
+	return NewAliasesFromFile(aliasesPath)
+}
+
+// emojiFilePaths returns the paths of the files in the emoji directory in
+// lexical order, excluding the aliases sidecar file.
+func emojiFilePaths(emojiDirectoryPath string) (paths []string, err error) {
+	aliasesPath := filepath.Join(emojiDirectoryPath, AliasesFileName)
+	paths = make([]string, 0)
 	err = filepath.Walk(emojiDirectoryPath, func(path string, info os.FileInfo, itemError error) (walkError error) {
 		if itemError != nil {
-			return errors.Wrapf(err, "walking path failed, path: '%+v', info: '%+v'", path, info)
-		} else if info.IsDir() {
+			return errors.Wrapf(itemError, "walking path failed, path: '%+v', info: '%+v'", path, info)
+		} else if info.IsDir() ||
+			path == aliasesPath {
 			return nil
 		}
 
-		name, takenName := newEmojiNameFromFilePath(path, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix)
-		log.Printf("%s\n", filepath.Base(path))
-		log.Printf("sanitized prefixed and suffixed name: %+v\n", name)
-
-		err = client.PostEmoji(name, path)
-		if err != nil &&
-			err != errorEmojiExists &&
-			err != errorEmojiNameTaken {
-			return errors.Wrapf(err, "posting emoji failed, path: '%+v'", path)
-		} else if err != nil &&
-			err == errorEmojiExists {
-			log.Printf("skipped existing\n")
-			skipCount++
-		} else if err != nil &&
-			err == errorEmojiNameTaken {
-			log.Printf("name is taken by non-custom emoji, using taken prefixed and suffixed name: %+v", takenName)
-			err = client.PostEmoji(takenName, path)
-			if err != nil &&
-				err != errorEmojiExists &&
-				err != errorEmojiNameTaken {
-				return errors.Wrapf(err, "posting emoji failed, path: '%+v''", path)
-			} else if err != nil &&
-				err == errorEmojiExists {
-				log.Printf("skipped existing\n")
-				skipCount++
-			} else if err != nil &&
-				err == errorEmojiNameTaken {
-				return fmt.Errorf("original and taken names were already taken, taken name: '%+v'", takenName)
-			} else if err == nil {
-				log.Printf("uploaded")
-				uploadCount++
-			}
-		} else if err == nil {
-			log.Printf("uploaded")
-			uploadCount++
-		}
-
-		log.Printf("Skipped+Uploaded=Existing: %d+%d=%d (%.2f%%+%.2f%%=%.2f%%), Remaining: %d (%.2f%%), total: %d\n\n", skipCount, uploadCount, skipCount+uploadCount, float64(skipCount)/float64(totalCount)*100.0, float64(uploadCount)/float64(totalCount)*100.0, float64(skipCount+uploadCount)/float64(totalCount)*100.0, totalCount-(skipCount+uploadCount), float64(totalCount-(skipCount+uploadCount))/float64(totalCount)*100.0, totalCount)
+		paths = append(paths, path)
 
 		return nil
 	})
 	if err != nil {
-		return errors.Wrapf(err, "iterating emoji directory for uploading failed, emoji directory path: '%+v'", emojiDirectoryPath)
+		return nil, err
 	}
 
-	return nil
+	return paths, nil
 }
 
-// apiTokenFromHTMLRecursively takes a customize/emoji HTML response and parses
-// the API token out of it.
-func apiTokenFromHTMLRecursively(node *html.Node) (apiToken string) {
-	if node.Parent != nil &&
-		node.Parent.Parent != nil &&
-		node.Parent.Parent.Parent != nil &&
-		node.Parent.Parent.Parent.Parent != nil &&
-		node.Parent.Parent.Parent.Parent.Type == html.DocumentNode &&
-		node.Parent.Parent.Parent.Parent.Data == "" &&
-		node.Parent.Parent.Parent.Type == html.ElementNode &&
-		node.Parent.Parent.Parent.Data == "html" &&
-		node.Parent.Parent.Type == html.ElementNode &&
-		node.Parent.Parent.Data == "body" &&
-		node.Parent.Type == html.ElementNode &&
-		node.Parent.Data == "script" &&
-		len(node.Parent.Attr) != 0 &&
-		node.Parent.Attr[0].Key == "type" &&
-		node.Parent.Attr[0].Val == "text/javascript" &&
-		node.Type == html.TextNode &&
-		strings.Contains(node.Data, "api_token") {
-		groups := apiTokenRegex.FindStringSubmatch(node.Data)
-		if len(groups) == 2 {
-			return groups[1]
+// newClient instantiates a client without a backend limited to the rate
+// limit tier, validating its base URL.
+func newClient(slackBaseURL string, rateLimitTier RateLimitTier) (client *Client, err error) {
+	if slackBaseURL != "" {
+		baseURL, err := url.Parse(slackBaseURL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing base URL failed, base URL: '%+v'", slackBaseURL)
+		} else if baseURL.Scheme == "" ||
+			baseURL.Host == "" {
+			return nil, fmt.Errorf("base URL misses scheme or host, base URL: '%+v'", slackBaseURL)
 		}
 	}
 
-	for child := node.FirstChild; child != nil; child = child.NextSibling {
-		apiToken = apiTokenFromHTMLRecursively(child)
-
-		if apiToken != "" {
-			return apiToken
-		}
+	rateLimiter, err := NewRateLimiter(rateLimitTier)
+	if err != nil {
+		return nil, errors.Wrapf(err, "instantiating rate limiter failed, tier: '%+v'", rateLimitTier)
 	}
 
-	return ""
+	return &Client{
+		BaseURL:            strings.TrimSuffix(slackBaseURL, "/"),
+		CustomizeEmojiPath: "customize/emoji",
+		downloadClient: resty.NewWithClient(
+			&http.Client{
+				Timeout: 30 * time.Second,
+				Transport: &sizeLimitingTransport{
+					transport: http.DefaultTransport,
+				},
+			},
+		),
+		EmojiAddPath:       "api/emoji.add",
+		EmojiAdminListPath: "api/emoji.adminList",
+		EmojiRemovePath:    "api/emoji.remove",
+		newBackoffStrategy: func() (strategy backoff.BackOff) { return backoff.NewExponentialBackOff() },
+		RateLimiter:        rateLimiter,
+		restClient: resty.NewWithClient(
+			&http.Client{
+				Timeout: 30 * time.Second,
+			},
+		).SetRedirectPolicy(resty.RedirectPolicyFunc(func(request *http.Request, via []*http.Request) (err error) {
+			return http.ErrUseLastResponse // Note: Slack redirects expired sessions to its sign in page, which is told apart by the redirect response.
+		})),
+	}, nil
 }
 
 // newEmojiNameFromFilePath returns the prefixed and suffixed name and taken name from the
@@ -647,3 +898,220 @@ func newEmojiNameFromFilePath(path, prefix, suffix, takenPrefix, takenSuffix str
 
 	return name, takenPrefix + name + takenSuffix
 }
+
+// newSessionClient instantiates a client of the session backend authorized
+// by the cookie without its API token.
+func newSessionClient(slackBaseURL, slackTeamName, slackCookie string) (client *Client, err error) {
+	client, err = newClient(slackBaseURL, DefaultRateLimitTier)
+	if err != nil {
+		return nil, err
+	}
+
+	client.backend = &sessionBackend{
+		client: client,
+	}
+	client.cookie = slackCookie
+	client.restClient.SetHeader("Cookie", slackCookie)
+	client.TeamName = slackTeamName
+
+	return client, nil
+}
+
+// cacheAPIToken caches the API token discovered with the client's cookie,
+// only logging failures as the token can be discovered again.
+func (client *Client) cacheAPIToken(apiToken string) {
+	client.TokenCache.SetToken(client.Host(), client.cookie, apiToken)
+
+	err := client.TokenCache.Save()
+	if err != nil {
+		log.Printf("saving token cache failed, error: '%+v'\n", err)
+	}
+}
+
+// canRefreshAPIToken returns whether the client's API token is discovered
+// with a session cookie and can therefore be discovered again.
+func (client *Client) canRefreshAPIToken() (canRefresh bool) {
+	return client.cookie != ""
+}
+
+// currentAPIToken returns the API token the requests are authorized with.
+func (client *Client) currentAPIToken() (apiToken string) {
+	client.apiTokenMutex.RLock()
+	defer client.apiTokenMutex.RUnlock()
+
+	return client.apiToken
+}
+
+// aliasNames returns the sorted names of the known aliases of the emoji.
+func (client *Client) aliasNames(emojiName string) (names []string) {
+	client.emojisMutex.RLock()
+	defer client.emojisMutex.RUnlock()
+
+	names = make([]string, 0)
+	for name, emoji := range client.Emojis {
+		if emoji.AliasFor == emojiName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// emoji returns the known emoji identified by its name.
+func (client *Client) emoji(emojiName string) (emoji Emoji, isExisting bool) {
+	client.emojisMutex.RLock()
+	defer client.emojisMutex.RUnlock()
+
+	emoji, isExisting = client.Emojis[emojiName]
+
+	return emoji, isExisting
+}
+
+// emojiNames returns the sorted names of the known emojis.
+func (client *Client) emojiNames() (names []string) {
+	client.emojisMutex.RLock()
+	defer client.emojisMutex.RUnlock()
+
+	names = make([]string, 0, len(client.Emojis))
+	for name := range client.Emojis {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// postWithTakenName posts an emoji or alias under the specified name and
+// falls back to the taken name when the name is taken by a non-custom emoji,
+// returning the name it was posted under and the outcome of the post.
+func (client *Client) postWithTakenName(name, takenName string, post func(name string) (err error)) (postedName string, outcome UploadOutcome, err error) {
+	outcome = UploadOutcomeUploaded
+
+	err = post(name)
+	if errors.Is(err, ErrorEmojiNameTaken) {
+		log.Printf("%s: name is taken by non-custom emoji, using taken prefixed and suffixed name: %+v\n", name, takenName)
+
+		name = takenName
+		outcome = UploadOutcomeNameTaken
+		err = post(name)
+		if errors.Is(err, ErrorEmojiNameTaken) {
+			return name, UploadOutcomeFailed, fmt.Errorf("original and taken names were already taken, taken name: '%+v'", takenName)
+		}
+	}
+
+	switch {
+	case err == nil:
+		return name, outcome, nil
+	case errors.Is(err, ErrorEmojiExists):
+		return name, UploadOutcomeSkipped, nil
+	default:
+		return name, UploadOutcomeFailed, errors.Wrapf(err, "posting failed, name: '%+v'", name)
+	}
+}
+
+// redact returns the text with the client's cookie, API token and every
+// other recognized secret redacted.
+func (client *Client) redact(text string) (redactedText string) {
+	if client == nil {
+		return Redact(text)
+	}
+
+	return Redact(text, client.cookie, client.currentAPIToken())
+}
+
+// refreshAPIToken discovers the API token again after Slack rejected the
+// stale token, unless a concurrent request refreshed it already, and swaps it
+// into the subsequent requests.
+func (client *Client) refreshAPIToken(ctx context.Context, staleToken string) (err error) {
+	client.apiTokenRefreshMutex.Lock()
+	defer client.apiTokenRefreshMutex.Unlock()
+
+	if client.currentAPIToken() != staleToken {
+		return nil
+	}
+
+	log.Printf("API token is rejected, discovering it again\n")
+
+	apiToken, err := client.APITokenContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	client.apiTokenMutex.Lock()
+	client.apiToken = apiToken
+	client.apiTokenMutex.Unlock()
+
+	client.cacheAPIToken(apiToken)
+
+	return nil
+}
+
+// replaceEmoji deletes the emoji and posts its replacement under the same
+// name, adding the aliases Slack removed along with the emoji again. The
+// aliases failed to be added again are returned in the error.
+func (client *Client) replaceEmoji(ctx context.Context, emojiName string, post func() (err error)) (err error) {
+	aliasNames := client.aliasNames(emojiName)
+
+	err = client.DeleteEmojiContext(ctx, emojiName)
+	if err != nil {
+		return errors.Wrapf(err, "deleting replaced emoji failed, name: '%+v'", emojiName)
+	}
+
+	err = post()
+	if err != nil {
+		return errors.Wrapf(err, "posting replacement emoji failed, name: '%+v', lost aliases: '%+v'", emojiName, aliasNames)
+	}
+
+	failedAliasNames := make([]string, 0)
+	aliasError := (error)(nil)
+	for _, aliasName := range aliasNames {
+		err = client.PostAliasContext(ctx, aliasName, emojiName)
+		if err != nil {
+			failedAliasNames = append(failedAliasNames, aliasName)
+			aliasError = err
+
+			continue
+		}
+
+		log.Printf("%s: alias for %s added again\n", aliasName, emojiName)
+	}
+
+	if len(failedAliasNames) != 0 {
+		return errors.Wrapf(aliasError, "adding aliases of replaced emoji again failed, name: '%+v', lost aliases: '%+v'", emojiName, failedAliasNames)
+	}
+
+	return nil
+}
+
+// setAPIToken sets the client's API token cached for its cookie or discovers
+// and caches it when it is not cached. A cached token rejected by Slack is
+// refreshed by the request pipeline.
+func (client *Client) setAPIToken(ctx context.Context) (err error) {
+	apiToken, isCached := client.TokenCache.Token(client.Host(), client.cookie)
+	if !isCached {
+		apiToken, err = client.APITokenContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		client.cacheAPIToken(apiToken)
+	}
+
+	client.apiTokenMutex.Lock()
+	client.apiToken = apiToken
+	client.apiTokenMutex.Unlock()
+
+	return nil
+}
+
+// setEmoji records an emoji as known.
+func (client *Client) setEmoji(emoji Emoji) {
+	client.emojisMutex.Lock()
+	defer client.emojisMutex.Unlock()
+
+	if client.Emojis == nil {
+		client.Emojis = make(map[string]Emoji)
+	}
+	client.Emojis[emoji.Name] = emoji
+}
@@ -0,0 +1,13 @@
+package slack
+
+// ClientOptions describes the optional behaviour of session clients.
+type ClientOptions struct {
+	// BaseURL is the URL the requests are sent to instead of the team's
+	// default https://<team>.slack.com host when it is not empty.
+	BaseURL string
+
+	// TokenCache reuses the API token cached for the cookie instead of
+	// discovering it when set, discovering and caching it again when it is
+	// missing, expired or rejected by Slack.
+	TokenCache *TokenCache
+}
@@ -0,0 +1,397 @@
+package slack_test
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+	"github.com/pregnor/slack-emoji-upload/slack/slacktest"
+)
+
+func TestClientString(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	client := newTestClient(t, server)
+
+	for _, text := range []string{client.String(), fmt.Sprintf("%+v", client)} {
+		if strings.Contains(text, slacktest.DefaultAPIToken) ||
+			strings.Contains(text, "d=slacktest") {
+			t.Errorf("client text leaks a secret, text: '%+v'", text)
+		} else if !strings.Contains(text, slack.RedactedText) ||
+			!strings.Contains(text, server.URL()) {
+			t.Errorf("client text misses the redacted secrets or the base URL, text: '%+v'", text)
+		}
+	}
+
+	if text := (*slack.Client)(nil).String(); text != "<nil>" {
+		t.Errorf("nil client text mismatches, expected: '%+v', actual: '%+v'", "<nil>", text)
+	}
+}
+
+func TestDeleteEmoji(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("party", newPNG(t, color.White))
+	client := newTestClient(t, server)
+
+	err := client.DeleteEmoji("party")
+	if err != nil {
+		t.Fatalf("deleting emoji failed, error: '%+v'", err)
+	}
+
+	if _, isExisting := server.Emojis()["party"]; isExisting {
+		t.Errorf("deleted emoji is still served")
+	} else if _, isExisting := client.Emojis["party"]; isExisting {
+		t.Errorf("deleted emoji is still known to the client")
+	}
+
+	err = client.DeleteEmoji("party")
+	if !errors.Is(err, slack.ErrorEmojiDoesNotExist) {
+		t.Errorf("deleting removed emoji returned unexpected error, expected: '%+v', actual: '%+v'", slack.ErrorEmojiDoesNotExist, err)
+	}
+}
+
+func TestDeleteEmojiRemovesAliases(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("party", newPNG(t, color.White))
+	server.AddAlias("party-alias", "party")
+	client := newTestClient(t, server)
+
+	err := client.DeleteEmoji("party")
+	if err != nil {
+		t.Fatalf("deleting emoji failed, error: '%+v'", err)
+	}
+
+	if _, isExisting := server.Emojis()["party-alias"]; isExisting {
+		t.Errorf("alias of deleted emoji is still served")
+	} else if _, isExisting := client.Emojis["party-alias"]; isExisting {
+		t.Errorf("alias of deleted emoji is still known to the client")
+	}
+}
+
+func TestDeleteEmojis(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("one", newPNG(t, color.White))
+	server.AddEmoji("two", newPNG(t, color.Black))
+	client := newTestClient(t, server)
+
+	err := client.DeleteEmojis()
+	if err != nil {
+		t.Fatalf("deleting emojis failed, error: '%+v'", err)
+	}
+
+	if emojis := server.Emojis(); len(emojis) != 0 {
+		t.Errorf("deleted emojis are still served, emojis: '%+v'", emojis)
+	}
+}
+
+func TestDeleteEmojisWithOptionsDryRun(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("one", newPNG(t, color.White))
+	client := newTestClient(t, server)
+
+	buffer := bytes.Buffer{}
+	err := client.DeleteEmojisWithOptions(slack.DeleteOptions{
+		DryRun: &slack.DryRun{
+			Format: slack.PlanFormatJSON,
+			Writer: &buffer,
+		},
+	})
+	if err != nil {
+		t.Fatalf("planning emoji deletion failed, error: '%+v'", err)
+	}
+
+	if _, isExisting := server.Emojis()["one"]; !isExisting {
+		t.Errorf("dry run deleted emoji")
+	} else if !bytes.Contains(buffer.Bytes(), []byte(`"delete"`)) {
+		t.Errorf("deletion plan misses deletion, plan: '%s'", buffer.String())
+	}
+}
+
+func TestGetEmojisPaging(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	emojiCount := 2500
+	for index := 0; index < emojiCount; index++ {
+		server.AddEmoji(fmt.Sprintf("emoji-%04d", index), []byte("image"))
+	}
+
+	client := newTestClient(t, server)
+	if len(client.Emojis) != emojiCount {
+		t.Errorf("listed emoji count mismatches, expected: '%+v', actual: '%+v'", emojiCount, len(client.Emojis))
+	}
+
+	if count := server.RequestCount(slacktest.EmojiAdminListPath); count != 3 {
+		t.Errorf("listing page count mismatches, expected: '%+v', actual: '%+v'", 3, count)
+	}
+}
+
+func TestPostEmojiPermanentSlackError(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	server.InjectFaults(slacktest.EmojiAddPath, slacktest.Fault{SlackError: "no_permission"})
+
+	err := client.PostEmojiData("party", "party.png", newPNG(t, color.White))
+	if !errors.Is(err, slack.ErrorNoPermission) {
+		t.Errorf("posting emoji returned unexpected error, expected: '%+v', actual: '%+v'", slack.ErrorNoPermission, err)
+	}
+
+	if count := server.RequestCount(slacktest.EmojiAddPath); count != 1 {
+		t.Errorf("permanent error is retried, request count: '%+v'", count)
+	}
+}
+
+func TestPostEmojiRetryAfter(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	server.InjectFaults(slacktest.EmojiAddPath, slacktest.Fault{
+		RetryAfterSeconds: 1,
+		StatusCode:        http.StatusTooManyRequests,
+	})
+
+	startTime := time.Now()
+	err := client.PostEmojiData("party", "party.png", newPNG(t, color.White))
+	if err != nil {
+		t.Fatalf("posting emoji failed, error: '%+v'", err)
+	}
+
+	if elapsed := time.Since(startTime); elapsed < time.Second {
+		t.Errorf("Retry-After delay is not waited out, elapsed: '%+v'", elapsed)
+	}
+
+	if count := server.RequestCount(slacktest.EmojiAddPath); count != 2 {
+		t.Errorf("request count mismatches, expected: '%+v', actual: '%+v'", 2, count)
+	} else if _, isExisting := server.Emojis()["party"]; !isExisting {
+		t.Errorf("emoji is not uploaded after the rate limit")
+	}
+}
+
+func TestPostEmojiServerErrorRetries(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	server.InjectFaults(
+		slacktest.EmojiAddPath,
+		slacktest.Fault{StatusCode: http.StatusInternalServerError},
+		slacktest.Fault{StatusCode: http.StatusServiceUnavailable},
+	)
+
+	err := client.PostEmojiData("party", "party.png", newPNG(t, color.White))
+	if err != nil {
+		t.Fatalf("posting emoji failed, error: '%+v'", err)
+	}
+
+	if count := server.RequestCount(slacktest.EmojiAddPath); count != 3 {
+		t.Errorf("request count mismatches, expected: '%+v', actual: '%+v'", 3, count)
+	}
+
+	image, isExisting := server.Image("party")
+	if !isExisting ||
+		!bytes.Equal(image, newPNG(t, color.White)) {
+		t.Errorf("uploaded image mismatches, is existing: '%+v'", isExisting)
+	}
+}
+
+func TestPostEmojiURL(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("party", newPNG(t, color.White))
+	client := newTestClient(t, server)
+
+	err := client.PostEmojiURL("party-copy", server.Emojis()["party"].URL)
+	if err != nil {
+		t.Fatalf("posting emoji URL failed, error: '%+v'", err)
+	}
+
+	image, isExisting := server.Image("party-copy")
+	if !isExisting ||
+		!bytes.Equal(image, newPNG(t, color.White)) {
+		t.Errorf("copied image mismatches, is existing: '%+v'", isExisting)
+	}
+}
+
+func TestPostEmojiURLOversizedImage(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	client := newTestClient(t, server)
+
+	data := make([]byte, 4*slack.DefaultImageConstraints.MaxFileSize)
+	testCases := []struct {
+		caseDescription string
+		handler         http.HandlerFunc
+	}{
+		{
+			caseDescription: "declared size",
+			handler: func(writer http.ResponseWriter, request *http.Request) {
+				writer.Header().Set("Content-Type", "image/png")
+				_, _ = writer.Write(data)
+			},
+		},
+		{
+			caseDescription: "undeclared size",
+			handler: func(writer http.ResponseWriter, request *http.Request) {
+				writer.Header().Set("Content-Type", "image/png")
+				for offset := 0; offset < len(data); offset += 1024 {
+					_, _ = writer.Write(data[offset : offset+1024])
+					writer.(http.Flusher).Flush()
+				}
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			requestCount := int32(0)
+			imageServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				atomic.AddInt32(&requestCount, 1)
+				testCase.handler(writer, request)
+			}))
+			defer imageServer.Close()
+
+			err := client.PostEmojiURL("oversized", imageServer.URL+"/oversized.png")
+			if !errors.Is(err, slack.ErrorInvalidEmojiImage) {
+				t.Errorf("posting emoji URL returned unexpected error, expected: '%+v', actual: '%+v'", slack.ErrorInvalidEmojiImage, err)
+			}
+
+			if count := atomic.LoadInt32(&requestCount); count != 1 {
+				t.Errorf("oversized image download is retried, request count: '%+v'", count)
+			} else if count := server.RequestCount(slacktest.EmojiAddPath); count != 0 {
+				t.Errorf("oversized image is uploaded, request count: '%+v'", count)
+			}
+		})
+	}
+}
+
+func TestPostEmojisNameTaken(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.ReserveNames("smile")
+	client := newTestClient(t, server)
+
+	directoryPath, removeDirectory := newTempDirectory(t)
+	defer removeDirectory()
+	writeFile(t, filepath.Join(directoryPath, "smile.png"), newPNG(t, color.White))
+	writeFile(t, filepath.Join(directoryPath, "wave.png"), newPNG(t, color.Black))
+
+	err := client.PostEmojis(directoryPath, "", "", "", "-2")
+	if err != nil {
+		t.Fatalf("posting emojis failed, error: '%+v'", err)
+	}
+
+	emojis := server.Emojis()
+	for _, name := range []string{"smile-2", "wave"} {
+		if _, isExisting := emojis[name]; !isExisting {
+			t.Errorf("emoji is not uploaded, name: '%+v', emojis: '%+v'", name, emojis)
+		}
+	}
+
+	if _, isExisting := emojis["smile"]; isExisting {
+		t.Errorf("emoji is uploaded under the taken name")
+	}
+}
+
+// newPNG returns a PNG image of a single color.
+func newPNG(t *testing.T, imageColor color.Color) (data []byte) {
+	t.Helper()
+
+	rgbaImage := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			rgbaImage.Set(x, y, imageColor)
+		}
+	}
+
+	buffer := bytes.Buffer{}
+	err := png.Encode(&buffer, rgbaImage)
+	if err != nil {
+		t.Fatalf("encoding PNG failed, error: '%+v'", err)
+	}
+
+	return buffer.Bytes()
+}
+
+// newTempDirectory creates a temporary directory and returns its path with
+// the function removing it.
+func newTempDirectory(t *testing.T) (path string, remove func()) {
+	t.Helper()
+
+	path, err := ioutil.TempDir("", "slack-test")
+	if err != nil {
+		t.Fatalf("creating temporary directory failed, error: '%+v'", err)
+	}
+
+	return path, func() { _ = os.RemoveAll(path) }
+}
+
+// newTestAdminClient returns an admin client of the server without rate limit
+// and backoff delays.
+func newTestAdminClient(t *testing.T, server *slacktest.Server) (client *slack.Client) {
+	t.Helper()
+
+	client, err := slack.NewAdminClient(server.URL(), slacktest.DefaultAPIToken)
+	if err != nil {
+		t.Fatalf("instantiating admin client failed, error: '%+v'", err)
+	}
+
+	slack.DisableDelays(client)
+
+	return client
+}
+
+// newTestClient returns a session client of the server without rate limit
+// and backoff delays.
+func newTestClient(t *testing.T, server *slacktest.Server) (client *slack.Client) {
+	t.Helper()
+
+	client, err := slack.NewSlackClientWithOptions("slacktest", "d=slacktest", slack.ClientOptions{BaseURL: server.URL()})
+	if err != nil {
+		t.Fatalf("instantiating client failed, error: '%+v'", err)
+	}
+
+	slack.DisableDelays(client)
+
+	return client
+}
+
+// writeFile writes the data into the file at the path.
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+
+	err := ioutil.WriteFile(path, data, 0644)
+	if err != nil {
+		t.Fatalf("writing file failed, path: '%+v', error: '%+v'", path, err)
+	}
+}
@@ -0,0 +1,178 @@
+package slack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ContentHashCache persists the content hashes of remote emoji images keyed by
+// their URLs, so unchanged emojis are compared without downloading them again.
+// Slack serves a changed image under a new URL, making the entries immutable.
+type ContentHashCache struct {
+	hashes map[string]string
+	mutex  sync.Mutex
+	path   string
+}
+
+// OpenContentHashCache loads the content hash cache file at the specified
+// path, starting empty when the file does not exist yet.
+func OpenContentHashCache(cachePath string) (cache *ContentHashCache, err error) {
+	if cachePath == "" {
+		return nil, fmt.Errorf("content hash cache path is empty")
+	}
+
+	cache = &ContentHashCache{
+		hashes: make(map[string]string),
+		path:   cachePath,
+	}
+
+	data, err := ioutil.ReadFile(cachePath)
+	if os.IsNotExist(err) {
+		return cache, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "reading content hash cache file failed, path: '%+v'", cachePath)
+	}
+
+	err = json.Unmarshal(data, &cache.hashes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling content hash cache failed, path: '%+v'", cachePath)
+	}
+
+	return cache, nil
+}
+
+// Hash returns the cached content hash of the specified emoji URL.
+func (cache *ContentHashCache) Hash(emojiURL string) (hash string, isExisting bool) {
+	if cache == nil {
+		return "", false
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	hash, isExisting = cache.hashes[emojiURL]
+
+	return hash, isExisting
+}
+
+// Save writes the cached content hashes to the cache file atomically.
+func (cache *ContentHashCache) Save() (err error) {
+	if cache == nil {
+		return nil
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	data, err := json.MarshalIndent(cache.hashes, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "marshalling content hash cache failed, path: '%+v'", cache.path)
+	}
+
+	err = writeFileAtomically(cache.path, append(data, '\n'), 0644)
+	if err != nil {
+		return errors.Wrapf(err, "writing content hash cache file failed, path: '%+v'", cache.path)
+	}
+
+	return nil
+}
+
+// SetHash caches the content hash of the specified emoji URL.
+func (cache *ContentHashCache) SetHash(emojiURL, hash string) {
+	if cache == nil {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.hashes[emojiURL] = hash
+}
+
+// deleteHash removes the cached content hash of the specified emoji URL, so a
+// server reusing the URL of a deleted emoji is not compared to a stale hash.
+func (cache *ContentHashCache) deleteHash(emojiURL string) {
+	if cache == nil {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	delete(cache.hashes, emojiURL)
+}
+
+// isEmojiChanged returns whether the remote emoji's image differs from the
+// content of the emoji file as it would be uploaded.
+func (client *Client) isEmojiChanged(ctx context.Context, remoteEmoji Emoji, path string) (isChanged bool, err error) {
+	localData, err := client.emojiFileData(path)
+	if err != nil {
+		return false, errors.Wrapf(err, "preparing emoji file failed, path: '%+v'", path)
+	}
+
+	remoteHash, err := client.remoteContentHash(ctx, remoteEmoji)
+	if err != nil {
+		return false, errors.Wrapf(err, "hashing remote emoji failed, name: '%+v'", remoteEmoji.Name)
+	}
+
+	return remoteHash != contentHash(localData), nil
+}
+
+// remoteContentHash returns the content hash of the remote emoji's image,
+// downloading and caching it unless it is cached already.
+func (client *Client) remoteContentHash(ctx context.Context, remoteEmoji Emoji) (hash string, err error) {
+	if hash, isExisting := client.ContentHashCache.Hash(remoteEmoji.URL); isExisting {
+		return hash, nil
+	}
+
+	data, _, err := client.DownloadEmojiContext(ctx, remoteEmoji)
+	if err != nil {
+		return "", errors.Wrapf(err, "downloading remote emoji failed, name: '%+v'", remoteEmoji.Name)
+	}
+
+	hash = contentHash(data)
+	client.ContentHashCache.SetHash(remoteEmoji.URL, hash)
+
+	return hash, nil
+}
+
+// replaceChangedEmoji deletes and uploads the existing image emoji again when
+// its image differs from the content of the emoji file, adding its aliases
+// again afterwards.
+func (client *Client) replaceChangedEmoji(ctx context.Context, emojiName, emojiPath string) (isReplaced bool, err error) {
+	remoteEmoji, isExisting := client.emoji(emojiName)
+	if !isExisting {
+		return false, ErrorEmojiDoesNotExist
+	} else if remoteEmoji.IsAliasEmoji() {
+		return false, nil
+	}
+
+	isChanged, err := client.isEmojiChanged(ctx, remoteEmoji, emojiPath)
+	if err != nil {
+		return false, errors.Wrapf(err, "comparing emoji content failed, name: '%+v'", emojiName)
+	} else if !isChanged {
+		return false, nil
+	}
+
+	err = client.replaceEmoji(ctx, emojiName, func() (err error) { return client.PostEmojiContext(ctx, emojiName, emojiPath) })
+	if err != nil {
+		return false, errors.Wrapf(err, "replacing changed emoji failed, name: '%+v', path: '%+v'", emojiName, emojiPath)
+	}
+
+	return true, nil
+}
+
+// contentHash returns the hex encoded SHA-256 hash of the data.
+func contentHash(data []byte) (hash string) {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,47 @@
+package slack_test
+
+import (
+	"bytes"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+	"github.com/pregnor/slack-emoji-upload/slack/slacktest"
+)
+
+func TestPostEmojisWithOptionsReplaceChangedKeepsAliases(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("party", newPNG(t, color.White))
+	server.AddEmoji("wave", newPNG(t, color.White))
+	server.AddAlias("party-alias", "party")
+	client := newTestClient(t, server)
+
+	directoryPath, removeDirectory := newTempDirectory(t)
+	defer removeDirectory()
+	writeFile(t, filepath.Join(directoryPath, "party.png"), newPNG(t, color.Black))
+	writeFile(t, filepath.Join(directoryPath, "wave.png"), newPNG(t, color.White))
+
+	report := &slack.UploadReport{}
+	err := client.PostEmojisWithOptions(directoryPath, "", "", "", "-2", slack.UploadOptions{
+		IsReplacingChanged: true,
+		Report:             report,
+	})
+	if err != nil {
+		t.Fatalf("posting emojis failed, error: '%+v'", err)
+	}
+
+	if count := report.Count(slack.UploadOutcomeReplaced); count != 1 {
+		t.Errorf("replaced count mismatches, expected: '%+v', actual: '%+v'", 1, count)
+	} else if count := report.Count(slack.UploadOutcomeSkipped); count != 1 {
+		t.Errorf("skipped count mismatches, expected: '%+v', actual: '%+v'", 1, count)
+	}
+
+	if image, _ := server.Image("party"); !bytes.Equal(image, newPNG(t, color.Black)) {
+		t.Errorf("changed emoji image is not replaced")
+	} else if emoji := server.Emojis()["party-alias"]; emoji.AliasFor != "party" {
+		t.Errorf("alias of replaced emoji is lost, emoji: '%+v'", emoji)
+	}
+}
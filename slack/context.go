@@ -0,0 +1,40 @@
+package slack
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// retryError returns the error of a failed retry loop, the context's error
+// when the loop was cancelled between attempts and the error of the last
+// attempt otherwise.
+func retryError(ctx context.Context, innerError error) (err error) {
+	if ctx.Err() == nil ||
+		errors.Is(innerError, ctx.Err()) {
+		return innerError
+	} else if innerError == nil {
+		return errors.WithMessage(ctx.Err(), "retrying cancelled")
+	}
+
+	return errors.WithMessagef(ctx.Err(), "retrying cancelled, last error: '%v'", innerError)
+}
+
+// sleepContext blocks for the specified duration or until the context is
+// done, returning the context's error in the latter case.
+func sleepContext(ctx context.Context, duration time.Duration) (err error) {
+	if duration <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
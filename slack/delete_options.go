@@ -0,0 +1,7 @@
+package slack
+
+// DeleteOptions describes the optional behaviour of bulk emoji deletions.
+type DeleteOptions struct {
+	// DryRun writes the deletion plan instead of deleting when set.
+	DryRun *DryRun
+}
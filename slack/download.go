@@ -0,0 +1,240 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/resty.v1"
+)
+
+var (
+	emojiExtensionsByContentType = map[string]string{
+		"image/gif":  ".gif",
+		"image/jpeg": ".jpg",
+		"image/png":  ".png",
+		"image/webp": ".webp",
+	}
+)
+
+// maxDownloadSizeContextKey is the context key of the response body size
+// limit of the download client's requests.
+type maxDownloadSizeContextKey struct{}
+
+// DownloadOptions describes the optional behaviour of bulk emoji downloads.
+type DownloadOptions struct {
+	// IsSkippingExisting skips emojis with an image file already present in
+	// the directory instead of downloading them again.
+	IsSkippingExisting bool
+}
+
+// DownloadEmoji fetches the image of the specified image emoji from its URL,
+// returning the image data with its file extension derived from its content
+// type.
+func (client *Client) DownloadEmoji(emoji Emoji) (data []byte, extension string, err error) {
+	return client.DownloadEmojiContext(context.Background(), emoji)
+}
+
+// DownloadEmojiContext is DownloadEmoji with a context cancelling its requests,
+// retries and rate limit waits.
+func (client *Client) DownloadEmojiContext(ctx context.Context, emoji Emoji) (data []byte, extension string, err error) {
+	if client == nil {
+		return nil, "", fmt.Errorf("client is nil")
+	} else if emoji.IsAliasEmoji() {
+		return nil, "", fmt.Errorf("alias emoji has no image, name: '%+v', alias for: '%+v'", emoji.Name, emoji.AliasFor)
+	} else if emoji.URL == "" {
+		return nil, "", fmt.Errorf("emoji URL is empty, name: '%+v'", emoji.Name)
+	}
+
+	response, err := client.sendRequest(ctx, slackRequest{
+		description: fmt.Sprintf("emoji image of '%s'", emoji.Name),
+		method:      http.MethodGet,
+		newRequest: func() (request *resty.Request) {
+			return client.downloadClient.R().SetContext(ctx)
+		},
+		uri: emoji.URL,
+	})
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "downloading emoji image failed, URL: '%+v'", emoji.URL)
+	}
+
+	data = response.Body()
+	extension, err = emojiExtension(response.Header().Get("Content-Type"), data)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "determining emoji file extension failed, name: '%+v'", emoji.Name)
+	}
+
+	return data, extension, nil
+}
+
+// DownloadEmojis saves the image of every custom image emoji into the
+// specified directory named after the emoji and writes a manifest of all
+// custom emojis including the aliases next to them.
+func (client *Client) DownloadEmojis(directoryPath string, options DownloadOptions) (err error) {
+	return client.DownloadEmojisContext(context.Background(), directoryPath, options)
+}
+
+// DownloadEmojisContext is DownloadEmojis with a context cancelling its
+// requests, retries and rate limit waits.
+func (client *Client) DownloadEmojisContext(ctx context.Context, directoryPath string, options DownloadOptions) (err error) {
+	if client == nil {
+		return fmt.Errorf("client is nil")
+	} else if directoryPath == "" {
+		return fmt.Errorf("invalid empty directory path")
+	}
+
+	err = os.MkdirAll(directoryPath, 0755)
+	if err != nil {
+		return errors.Wrapf(err, "creating directory failed, path: '%+v'", directoryPath)
+	}
+
+	client.emojisMutex.RLock()
+	emojis := make(map[string]Emoji, len(client.Emojis))
+	for name, emoji := range client.Emojis {
+		emojis[name] = emoji
+	}
+	client.emojisMutex.RUnlock()
+
+	manifest := NewManifest(client.TeamName, emojis)
+	downloadCount := 0
+	skipCount := 0
+	for manifestIndex := range manifest.Emojis {
+		manifestEmoji := &manifest.Emojis[manifestIndex]
+		if manifestEmoji.IsAlias() {
+			continue
+		}
+
+		if options.IsSkippingExisting {
+			manifestEmoji.FileName = existingEmojiFileName(directoryPath, manifestEmoji.Name)
+			if manifestEmoji.FileName != "" {
+				log.Printf("%s: skipped existing %s\n", manifestEmoji.Name, manifestEmoji.FileName)
+				skipCount++
+
+				continue
+			}
+		}
+
+		data, extension, err := client.DownloadEmojiContext(ctx, emojis[manifestEmoji.Name])
+		if err != nil {
+			return errors.Wrapf(err, "downloading emoji failed, name: '%+v'", manifestEmoji.Name)
+		}
+
+		manifestEmoji.FileName = manifestEmoji.Name + extension
+		err = writeFileAtomically(filepath.Join(directoryPath, manifestEmoji.FileName), data, 0644)
+		if err != nil {
+			return errors.Wrapf(err, "saving emoji failed, name: '%+v'", manifestEmoji.Name)
+		}
+
+		log.Printf("%s: downloaded as %s\n", manifestEmoji.Name, manifestEmoji.FileName)
+		downloadCount++
+	}
+
+	err = manifest.WriteFile(filepath.Join(directoryPath, ManifestFileName))
+	if err != nil {
+		return errors.Wrapf(err, "writing manifest failed, directory path: '%+v'", directoryPath)
+	}
+
+	log.Printf("Downloaded: %d, skipped: %d, emojis: %d\n", downloadCount, skipCount, len(manifest.Emojis))
+
+	return nil
+}
+
+// emojiExtension returns the image file extension of the specified content
+// type, falling back to sniffing the data.
+func emojiExtension(contentType string, data []byte) (extension string, err error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil {
+		if extension, isExisting := emojiExtensionsByContentType[strings.ToLower(mediaType)]; isExisting {
+			return extension, nil
+		}
+	}
+
+	sniffedContentType := http.DetectContentType(data)
+	mediaType, _, err = mime.ParseMediaType(sniffedContentType)
+	if err == nil {
+		if extension, isExisting := emojiExtensionsByContentType[mediaType]; isExisting {
+			return extension, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported emoji content type, content type: '%+v', sniffed content type: '%+v'", contentType, sniffedContentType)
+}
+
+// existingEmojiFileName returns the name of the image file already saved for
+// the emoji in the directory or an empty string if there is none.
+func existingEmojiFileName(directoryPath, emojiName string) (fileName string) {
+	for _, extension := range emojiExtensionsByContentType {
+		fileName = emojiName + extension
+		if _, err := os.Stat(filepath.Join(directoryPath, fileName)); err == nil {
+			return fileName
+		}
+	}
+
+	return ""
+}
+
+// sizeLimitedBody is a response body failing with ErrorInvalidEmojiImage as
+// soon as more than the maximum size is read from it.
+type sizeLimitedBody struct {
+	io.ReadCloser
+	maxSize  int64
+	readSize int64
+}
+
+// Read reads from the response body, failing when the read data exceeds the
+// maximum size.
+func (body *sizeLimitedBody) Read(buffer []byte) (count int, err error) {
+	count, err = body.ReadCloser.Read(buffer)
+	body.readSize += int64(count)
+	if body.readSize > body.maxSize {
+		return count, errors.Wrapf(ErrorInvalidEmojiImage, "image size exceeds the maximum of %d B", body.maxSize)
+	}
+
+	return count, err
+}
+
+// sizeLimitingTransport is the transport of the download client, limiting the
+// response bodies of the requests with a maximum download size set in their
+// context, see withMaxDownloadSize.
+type sizeLimitingTransport struct {
+	transport http.RoundTripper
+}
+
+// RoundTrip sends the request, rejecting responses declaring a larger body
+// than the maximum download size before reading them and limiting the read
+// size of the others.
+func (transport *sizeLimitingTransport) RoundTrip(request *http.Request) (response *http.Response, err error) {
+	response, err = transport.transport.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSize, _ := request.Context().Value(maxDownloadSizeContextKey{}).(int64)
+	if maxSize <= 0 {
+		return response, nil
+	} else if response.ContentLength > maxSize {
+		_ = response.Body.Close()
+
+		return nil, errors.Wrapf(ErrorInvalidEmojiImage, "image size %d B exceeds the maximum of %d B", response.ContentLength, maxSize)
+	}
+
+	response.Body = &sizeLimitedBody{
+		ReadCloser: response.Body,
+		maxSize:    maxSize,
+	}
+
+	return response, nil
+}
+
+// withMaxDownloadSize returns the context limiting the size of the emoji
+// images downloaded with it, zero does not limit it.
+func withMaxDownloadSize(ctx context.Context, maxSize int64) (limitedContext context.Context) {
+	return context.WithValue(ctx, maxDownloadSizeContextKey{}, maxSize)
+}
@@ -15,3 +15,9 @@ type Emoji struct {
 	UserDisplayName string   `json:"user_display_name"`
 	UserID          string   `json:"user_id"`
 }
+
+// IsAliasEmoji returns whether the emoji is an alias of another emoji.
+func (emoji Emoji) IsAliasEmoji() (isAlias bool) {
+	return emoji.IsAlias != 0 ||
+		emoji.AliasFor != ""
+}
@@ -6,6 +6,7 @@ type EmojiListResponse struct {
 	CustomEmojiTotalCount int64   `json:"custom_emoji_total_count"`
 	DisabledEmojis        []Emoji `json:"disabled_emoji"`
 	Emojis                []Emoji `json:"emoji"`
+	Error                 string  `json:"error"`
 	IsOk                  bool    `json:"ok"`
 	Paging                Paging  `json:"paging"`
 }
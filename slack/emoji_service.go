@@ -0,0 +1,30 @@
+package slack
+
+import (
+	"context"
+)
+
+// EmojiService describes the management of the custom emojis of a Slack team,
+// letting callers depend on the operations instead of a concrete client.
+// Client implements it against Slack and MemoryEmojiService in memory.
+type EmojiService interface {
+	// DeleteEmojiContext removes the emoji of the name.
+	DeleteEmojiContext(ctx context.Context, emojiName string) (err error)
+
+	// GetEmojisContext lists the custom emojis by name.
+	GetEmojisContext(ctx context.Context) (emojis map[string]Emoji, err error)
+
+	// PostAliasContext adds an alias under the name to an existing emoji.
+	PostAliasContext(ctx context.Context, aliasName, targetName string) (err error)
+
+	// PostEmojiDataContext adds an emoji of the image data under the name.
+	PostEmojiDataContext(ctx context.Context, emojiName, fileName string, data []byte) (err error)
+
+	// RenameEmojiContext renames an existing emoji.
+	RenameEmojiContext(ctx context.Context, emojiName, newName string) (err error)
+}
+
+var (
+	_ EmojiService = (*Client)(nil)
+	_ EmojiService = (*MemoryEmojiService)(nil)
+)
@@ -0,0 +1,185 @@
+package slack
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrorEmojiDoesNotExist signals an operation on an emoji unknown to the
+	// client.
+	ErrorEmojiDoesNotExist = fmt.Errorf("emoji does not exist")
+
+	// ErrorEmojiExists signals adding an emoji under the name of a custom
+	// emoji known to the client.
+	ErrorEmojiExists = fmt.Errorf("emoji already exists")
+
+	// ErrorInvalidCookie signals a session cookie Slack does not accept
+	// anymore, which is replaced by signing in with a browser again.
+	ErrorInvalidCookie = fmt.Errorf("session cookie is invalid or expired, sign in to the team in a browser and copy its `d` cookie into the configuration again")
+
+	// ErrorInvalidEmojiImage signals an emoji image violating the image
+	// constraints.
+	ErrorInvalidEmojiImage = fmt.Errorf("invalid emoji image")
+
+	// ErrorUnsupportedOperation signals an operation the client's backend
+	// does not support, e.g. uploading image data through the admin API.
+	ErrorUnsupportedOperation = fmt.Errorf("operation is not supported by the backend")
+)
+
+var (
+	// ErrorAccountInactive signals a deactivated user or team.
+	ErrorAccountInactive = &SlackError{Code: "account_inactive", IsPermanent: true}
+
+	// ErrorEmojiNameTaken signals adding an emoji under a name taken by a
+	// non-custom emoji.
+	ErrorEmojiNameTaken = &SlackError{Code: "error_name_taken", IsPermanent: true}
+
+	// ErrorEmojiNotFound signals removing an emoji unknown to Slack.
+	ErrorEmojiNotFound = &SlackError{Code: "emoji_not_found", IsPermanent: true}
+
+	// ErrorInvalidAlias signals adding an alias of an emoji unknown to Slack.
+	ErrorInvalidAlias = &SlackError{Code: "error_invalid_alias", IsPermanent: true}
+
+	// ErrorInvalidAuth signals an invalid API token.
+	ErrorInvalidAuth = &SlackError{Code: "invalid_auth", IsPermanent: true}
+
+	// ErrorInvalidImage signals an image Slack could not process.
+	ErrorInvalidImage = &SlackError{Code: "error_bad_format", IsPermanent: true}
+
+	// ErrorInvalidName signals an emoji name containing unsupported
+	// characters.
+	ErrorInvalidName = &SlackError{Code: "invalid_name", IsPermanent: true}
+
+	// ErrorNoImageUploaded signals an emoji addition without an image.
+	ErrorNoImageUploaded = &SlackError{Code: "no_image_uploaded", IsPermanent: true}
+
+	// ErrorNoPermission signals a user without the permission to manage
+	// emojis.
+	ErrorNoPermission = &SlackError{Code: "no_permission", IsPermanent: true}
+
+	// ErrorNotAuthed signals a request without an API token.
+	ErrorNotAuthed = &SlackError{Code: "not_authed", IsPermanent: true}
+
+	// ErrorResizedButStillTooLarge signals an image exceeding the size limit
+	// even after Slack resized it.
+	ErrorResizedButStillTooLarge = &SlackError{Code: "resized_but_still_too_large", IsPermanent: true}
+
+	// ErrorTokenExpired signals an expired API token.
+	ErrorTokenExpired = &SlackError{Code: "token_expired", IsPermanent: true}
+
+	// ErrorTokenRevoked signals a revoked API token.
+	ErrorTokenRevoked = &SlackError{Code: "token_revoked", IsPermanent: true}
+
+	// ErrorTooManyFrames signals an animated image with more frames than
+	// Slack accepts.
+	ErrorTooManyFrames = &SlackError{Code: "too_many_frames", IsPermanent: true}
+
+	slackErrorsByCode = map[string]*SlackError{
+		"account_inactive":            ErrorAccountInactive,
+		"emoji_not_found":             ErrorEmojiNotFound,
+		"error_bad_format":            ErrorInvalidImage,
+		"error_bad_upload":            ErrorInvalidImage,
+		"error_invalid_alias":         ErrorInvalidAlias,
+		"error_name_taken":            ErrorEmojiNameTaken,
+		"error_name_taken_i18n":       ErrorEmojiNameTaken,
+		"invalid_auth":                ErrorInvalidAuth,
+		"invalid_name":                ErrorInvalidName,
+		"no_image_uploaded":           ErrorNoImageUploaded,
+		"no_permission":               ErrorNoPermission,
+		"not_authed":                  ErrorNotAuthed,
+		"resized_but_still_too_large": ErrorResizedButStillTooLarge,
+		"token_expired":               ErrorTokenExpired,
+		"token_revoked":               ErrorTokenRevoked,
+		"too_many_frames":             ErrorTooManyFrames,
+	}
+)
+
+// SlackError describes an error code returned by the Slack API in a not OK
+// response.
+type SlackError struct {
+	Code        string
+	IsPermanent bool
+}
+
+// NewSlackError returns the error value of the specified Slack error code,
+// unknown codes result in a new transient error.
+func NewSlackError(code string) (slackError *SlackError) {
+	if slackError, isExisting := slackErrorsByCode[code]; isExisting {
+		return slackError
+	}
+
+	return &SlackError{
+		Code: code,
+	}
+}
+
+// StatusError describes an HTTP error status returned by Slack. Client
+// errors other than request timeouts and rate limits are permanent, server
+// errors are transient.
+type StatusError struct {
+	IsPermanent bool
+	RetryAfter  time.Duration
+	StatusCode  int
+}
+
+// NewStatusError returns the classified error of the specified HTTP error
+// status with the delay requested by the server before retrying.
+func NewStatusError(statusCode int, retryAfter time.Duration) (statusError *StatusError) {
+	return &StatusError{
+		IsPermanent: statusCode >= 400 &&
+			statusCode < 500 &&
+			statusCode != http.StatusRequestTimeout &&
+			statusCode != http.StatusTooManyRequests,
+		RetryAfter: retryAfter,
+		StatusCode: statusCode,
+	}
+}
+
+// IsPermanentError returns whether the error is or wraps a Slack or HTTP
+// status error or an invalid emoji image which retrying the request does not
+// resolve.
+func IsPermanentError(err error) (isPermanent bool) {
+	slackError := (*SlackError)(nil)
+	statusError := (*StatusError)(nil)
+
+	return (errors.As(err, &slackError) && slackError.IsPermanent) ||
+		(errors.As(err, &statusError) && statusError.IsPermanent) ||
+		errors.Is(err, ErrorInvalidEmojiImage)
+}
+
+// Error returns the Slack error code with the error's kind.
+func (slackError *SlackError) Error() (message string) {
+	if slackError.IsPermanent {
+		return "permanent Slack error: " + slackError.Code
+	}
+
+	return "transient Slack error: " + slackError.Code
+}
+
+// Error returns the HTTP status with the error's kind.
+func (statusError *StatusError) Error() (message string) {
+	status := fmt.Sprintf("%d %s", statusError.StatusCode, http.StatusText(statusError.StatusCode))
+	if statusError.IsPermanent {
+		return "permanent HTTP error: " + status
+	}
+
+	return "transient HTTP error: " + status
+}
+
+// isAuthError returns whether the error is or wraps a Slack error rejecting
+// the API token of the request.
+func isAuthError(err error) (isAuth bool) {
+	return errors.Is(err, ErrorInvalidAuth) ||
+		errors.Is(err, ErrorTokenExpired) ||
+		errors.Is(err, ErrorTokenRevoked)
+}
+
+// newSlackResponseError returns the error of the not OK Slack response with
+// the specified error code.
+func newSlackResponseError(code string, response interface{}) (err error) {
+	return errors.WithMessagef(NewSlackError(code), "not OK response received, response: '%+v'", response)
+}
@@ -0,0 +1,209 @@
+package slack_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+)
+
+func TestIsPermanentError(t *testing.T) {
+	testCases := []struct {
+		caseDescription string
+		err             error
+		expectedError   error
+		isPermanent     bool
+	}{
+		{
+			caseDescription: "account_inactive",
+			err:             slack.NewSlackError("account_inactive"),
+			expectedError:   slack.ErrorAccountInactive,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "emoji_not_found",
+			err:             slack.NewSlackError("emoji_not_found"),
+			expectedError:   slack.ErrorEmojiNotFound,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "error_bad_format",
+			err:             slack.NewSlackError("error_bad_format"),
+			expectedError:   slack.ErrorInvalidImage,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "error_bad_upload",
+			err:             slack.NewSlackError("error_bad_upload"),
+			expectedError:   slack.ErrorInvalidImage,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "error_invalid_alias",
+			err:             slack.NewSlackError("error_invalid_alias"),
+			expectedError:   slack.ErrorInvalidAlias,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "error_name_taken",
+			err:             slack.NewSlackError("error_name_taken"),
+			expectedError:   slack.ErrorEmojiNameTaken,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "error_name_taken_i18n",
+			err:             slack.NewSlackError("error_name_taken_i18n"),
+			expectedError:   slack.ErrorEmojiNameTaken,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "invalid_auth",
+			err:             slack.NewSlackError("invalid_auth"),
+			expectedError:   slack.ErrorInvalidAuth,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "invalid_name",
+			err:             slack.NewSlackError("invalid_name"),
+			expectedError:   slack.ErrorInvalidName,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "no_image_uploaded",
+			err:             slack.NewSlackError("no_image_uploaded"),
+			expectedError:   slack.ErrorNoImageUploaded,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "no_permission",
+			err:             slack.NewSlackError("no_permission"),
+			expectedError:   slack.ErrorNoPermission,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "not_authed",
+			err:             slack.NewSlackError("not_authed"),
+			expectedError:   slack.ErrorNotAuthed,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "resized_but_still_too_large",
+			err:             slack.NewSlackError("resized_but_still_too_large"),
+			expectedError:   slack.ErrorResizedButStillTooLarge,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "token_expired",
+			err:             slack.NewSlackError("token_expired"),
+			expectedError:   slack.ErrorTokenExpired,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "token_revoked",
+			err:             slack.NewSlackError("token_revoked"),
+			expectedError:   slack.ErrorTokenRevoked,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "too_many_frames",
+			err:             slack.NewSlackError("too_many_frames"),
+			expectedError:   slack.ErrorTooManyFrames,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "wrapped mapped code",
+			err:             errors.Wrap(slack.NewSlackError("no_permission"), "posting emoji failed"),
+			expectedError:   slack.ErrorNoPermission,
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "unknown code",
+			err:             slack.NewSlackError("internal_error"),
+			isPermanent:     false,
+		},
+		{
+			caseDescription: "empty code",
+			err:             slack.NewSlackError(""),
+			isPermanent:     false,
+		},
+		{
+			caseDescription: "400 Bad Request",
+			err:             slack.NewStatusError(http.StatusBadRequest, 0),
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "403 Forbidden",
+			err:             slack.NewStatusError(http.StatusForbidden, 0),
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "404 Not Found",
+			err:             slack.NewStatusError(http.StatusNotFound, 0),
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "408 Request Timeout",
+			err:             slack.NewStatusError(http.StatusRequestTimeout, 0),
+			isPermanent:     false,
+		},
+		{
+			caseDescription: "429 Too Many Requests",
+			err:             slack.NewStatusError(http.StatusTooManyRequests, 30*time.Second),
+			isPermanent:     false,
+		},
+		{
+			caseDescription: "500 Internal Server Error",
+			err:             slack.NewStatusError(http.StatusInternalServerError, 0),
+			isPermanent:     false,
+		},
+		{
+			caseDescription: "502 Bad Gateway",
+			err:             slack.NewStatusError(http.StatusBadGateway, 0),
+			isPermanent:     false,
+		},
+		{
+			caseDescription: "503 Service Unavailable",
+			err:             slack.NewStatusError(http.StatusServiceUnavailable, 0),
+			isPermanent:     false,
+		},
+		{
+			caseDescription: "wrapped 404 Not Found",
+			err:             errors.Wrap(slack.NewStatusError(http.StatusNotFound, 0), "listing emojis failed"),
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "invalid emoji image",
+			err:             errors.Wrap(slack.ErrorInvalidEmojiImage, "image size exceeds the maximum of 131072 B"),
+			isPermanent:     true,
+		},
+		{
+			caseDescription: "other error",
+			err:             fmt.Errorf("connection reset by peer"),
+			isPermanent:     false,
+		},
+		{
+			caseDescription: "nil error",
+			err:             nil,
+			isPermanent:     false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			if isPermanent := slack.IsPermanentError(testCase.err); isPermanent != testCase.isPermanent {
+				t.Errorf("permanence mismatches, expected: '%+v', actual: '%+v', error: '%+v'", testCase.isPermanent, isPermanent, testCase.err)
+			}
+
+			if testCase.expectedError != nil &&
+				!errors.Is(testCase.err, testCase.expectedError) {
+				t.Errorf("error is not mapped, expected: '%+v', actual: '%+v'", testCase.expectedError, testCase.err)
+			}
+		})
+	}
+}
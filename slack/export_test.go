@@ -0,0 +1,40 @@
+package slack
+
+import (
+	"strings"
+	"time"
+
+	backoff "github.com/cenkalti/backoff/v4"
+	"golang.org/x/net/html"
+)
+
+// APITokenStrategyResults returns the API tokens found in the page by each
+// discovery strategy in the order they are tried.
+func APITokenStrategyResults(page string) (apiTokens []string) {
+	document, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		return nil
+	}
+
+	for _, strategy := range apiTokenStrategies {
+		apiTokens = append(apiTokens, strategy(document, page))
+	}
+
+	return apiTokens
+}
+
+// DisableDelays turns the rate limiter of the client off and retries its
+// failed requests without a backoff delay a few times, so the tests do not
+// wait between requests.
+func DisableDelays(client *Client) {
+	client.RateLimiter = nil
+	client.newBackoffStrategy = func() (strategy backoff.BackOff) {
+		return backoff.WithMaxRetries(&backoff.ZeroBackOff{}, 5)
+	}
+}
+
+// RateLimiterInterval returns the interval the rate limiter spaces the
+// requests by.
+func RateLimiterInterval(limiter *RateLimiter) (interval time.Duration) {
+	return limiter.interval
+}
@@ -0,0 +1,95 @@
+package slack
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	_ "image/jpeg" // Registering the JPEG format for image.DecodeConfig.
+	_ "image/png"  // Registering the PNG format for image.DecodeConfig.
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// DefaultImageConstraints describes the limits Slack enforces on custom
+	// emoji images.
+	DefaultImageConstraints = ImageConstraints{
+		MaxFileSize:   128 * 1024,
+		MaxFrameCount: 100,
+		MaxHeight:     128,
+		MaxWidth:      128,
+	}
+
+	supportedImageFormats = map[string]bool{
+		"gif":  true,
+		"jpeg": true,
+		"png":  true,
+	}
+)
+
+// ImageConstraints describes the limits an emoji image has to respect to be
+// accepted by Slack, zero limits are not enforced.
+type ImageConstraints struct {
+	MaxFileSize   int64
+	MaxFrameCount int
+	MaxHeight     int
+	MaxWidth      int
+}
+
+// ValidateFile checks the emoji file at the specified path locally, returning
+// an error describing the reason of the rejection for unsupported formats,
+// non-image files, oversized files or images and animated GIFs with too many
+// frames.
+func (constraints *ImageConstraints) ValidateFile(emojiPath string) (err error) {
+	if constraints == nil {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(emojiPath)
+	if err != nil {
+		return errors.Wrapf(err, "reading emoji file failed, path: '%+v'", emojiPath)
+	}
+
+	return constraints.ValidateData(data)
+}
+
+// ValidateData checks the emoji image data locally, returning an error
+// describing the reason of the rejection for unsupported formats, non-image
+// data, oversized data or images and animated GIFs with too many frames.
+func (constraints *ImageConstraints) ValidateData(data []byte) (err error) {
+	if constraints == nil {
+		return nil
+	}
+
+	if constraints.MaxFileSize != 0 &&
+		int64(len(data)) > constraints.MaxFileSize {
+		return errors.Wrapf(ErrorInvalidEmojiImage, "file size %d B exceeds the maximum of %d B", len(data), constraints.MaxFileSize)
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrapf(ErrorInvalidEmojiImage, "file is not a PNG, JPEG or GIF image (%s)", err.Error())
+	} else if !supportedImageFormats[format] {
+		return errors.Wrapf(ErrorInvalidEmojiImage, "image format %s is not supported", format)
+	}
+
+	if (constraints.MaxWidth != 0 &&
+		config.Width > constraints.MaxWidth) ||
+		(constraints.MaxHeight != 0 &&
+			config.Height > constraints.MaxHeight) {
+		return errors.Wrapf(ErrorInvalidEmojiImage, "image dimensions %dx%d exceed the maximum of %dx%d", config.Width, config.Height, constraints.MaxWidth, constraints.MaxHeight)
+	}
+
+	if format == "gif" &&
+		constraints.MaxFrameCount != 0 {
+		animation, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return errors.Wrapf(ErrorInvalidEmojiImage, "GIF image is corrupt (%s)", err.Error())
+		} else if len(animation.Image) > constraints.MaxFrameCount {
+			return errors.Wrapf(ErrorInvalidEmojiImage, "GIF frame count %d exceeds the maximum of %d", len(animation.Image), constraints.MaxFrameCount)
+		}
+	}
+
+	return nil
+}
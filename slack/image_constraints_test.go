@@ -0,0 +1,135 @@
+package slack_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+)
+
+func TestImageConstraintsValidateData(t *testing.T) {
+	withoutDimensions := slack.DefaultImageConstraints
+	withoutDimensions.MaxHeight = 0
+	withoutDimensions.MaxWidth = 0
+
+	testCases := []struct {
+		caseDescription string
+		constraints     slack.ImageConstraints
+		data            []byte
+		isValid         bool
+	}{
+		{
+			caseDescription: "small PNG",
+			constraints:     slack.DefaultImageConstraints,
+			data:            newSizedPNG(t, 128, 128, false),
+			isValid:         true,
+		},
+		{
+			caseDescription: "large PNG with dimension limits",
+			constraints:     slack.DefaultImageConstraints,
+			data:            newSizedPNG(t, 256, 256, false),
+			isValid:         false,
+		},
+		{
+			caseDescription: "large PNG without dimension limits",
+			constraints:     withoutDimensions,
+			data:            newSizedPNG(t, 256, 256, false),
+			isValid:         true,
+		},
+		{
+			caseDescription: "oversized file",
+			constraints:     withoutDimensions,
+			data:            newSizedPNG(t, 256, 256, true),
+			isValid:         false,
+		},
+		{
+			caseDescription: "non-image file",
+			constraints:     withoutDimensions,
+			data:            []byte("Bud1\x00\x00\x00"),
+			isValid:         false,
+		},
+		{
+			caseDescription: "GIF of too many frames",
+			constraints:     withoutDimensions,
+			data:            newGIF(t, 101),
+			isValid:         false,
+		},
+		{
+			caseDescription: "GIF of maximum frames",
+			constraints:     withoutDimensions,
+			data:            newGIF(t, 100),
+			isValid:         true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			err := testCase.constraints.ValidateData(testCase.data)
+			if testCase.isValid &&
+				err != nil {
+				t.Errorf("valid image is rejected, error: '%+v'", err)
+			} else if !testCase.isValid &&
+				!errors.Is(err, slack.ErrorInvalidEmojiImage) {
+				t.Errorf("invalid image is not rejected, error: '%+v'", err)
+			}
+		})
+	}
+}
+
+// newGIF returns an animated GIF of the specified number of 8x8 frames.
+func newGIF(t *testing.T, frameCount int) (data []byte) {
+	t.Helper()
+
+	animation := &gif.GIF{}
+	for frameIndex := 0; frameIndex < frameCount; frameIndex++ {
+		frame := image.NewPaletted(image.Rect(0, 0, 8, 8), palette.Plan9)
+		frame.SetColorIndex(frameIndex%8, frameIndex%8, uint8(frameIndex))
+		animation.Image = append(animation.Image, frame)
+		animation.Delay = append(animation.Delay, 5)
+	}
+
+	buffer := bytes.Buffer{}
+	err := gif.EncodeAll(&buffer, animation)
+	if err != nil {
+		t.Fatalf("encoding GIF failed, error: '%+v'", err)
+	}
+
+	return buffer.Bytes()
+}
+
+// newSizedPNG returns a PNG image of the specified dimensions, filled with
+// noise failing compression when requested.
+func newSizedPNG(t *testing.T, width, height int, isNoisy bool) (data []byte) {
+	t.Helper()
+
+	random := rand.New(rand.NewSource(1))
+	rgbaImage := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pixelColor := color.RGBA{R: 200, G: 100, B: 50, A: 255}
+			if isNoisy {
+				pixelColor = color.RGBA{R: uint8(random.Intn(256)), G: uint8(random.Intn(256)), B: uint8(random.Intn(256)), A: 255}
+			}
+
+			rgbaImage.Set(x, y, pixelColor)
+		}
+	}
+
+	buffer := bytes.Buffer{}
+	err := png.Encode(&buffer, rgbaImage)
+	if err != nil {
+		t.Fatalf("encoding PNG failed, error: '%+v'", err)
+	}
+
+	return buffer.Bytes()
+}
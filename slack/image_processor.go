@@ -0,0 +1,325 @@
+package slack
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+	xdraw "golang.org/x/image/draw"
+)
+
+const (
+	// imageProcessorMinDimension is the size below which oversized images are
+	// not scaled down any further.
+	imageProcessorMinDimension = 8
+
+	// imageProcessorScaleStep is the ratio an image is scaled down with when
+	// its encoding exceeds the maximum file size.
+	imageProcessorScaleStep = 0.75
+)
+
+var (
+	// imageProcessorJPEGQualities are the JPEG qualities tried in order when
+	// recompressing a JPEG image.
+	imageProcessorJPEGQualities = []int{90, 75, 60, 45}
+)
+
+// ImageProcessor downscales and recompresses emoji images violating the
+// constraints before they are uploaded, keeping their format.
+type ImageProcessor struct {
+	// Constraints are the limits the processed images fit in, a zero maximum
+	// width or height does not limit the dimension.
+	Constraints ImageConstraints
+
+	// IsPaddingSquare centers non-square images on a square canvas, padded
+	// with transparency or white for JPEG images.
+	IsPaddingSquare bool
+}
+
+// Process returns the image data downscaled to the constraints' box keeping
+// its aspect ratio, limited to the maximum number of animation frames and
+// recompressed to fit under the maximum file size. Images already respecting
+// the constraints are returned unchanged.
+func (processor *ImageProcessor) Process(data []byte) (processedData []byte, err error) {
+	if processor == nil {
+		return data, nil
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrapf(ErrorInvalidEmojiImage, "file is not a PNG, JPEG or GIF image (%s)", err.Error())
+	} else if !supportedImageFormats[format] {
+		return nil, errors.Wrapf(ErrorInvalidEmojiImage, "image format %s is not supported", format)
+	}
+
+	if processor.Constraints.ValidateData(data) == nil &&
+		(!processor.IsPaddingSquare ||
+			config.Width == config.Height) {
+		return data, nil
+	}
+
+	if format == "gif" {
+		return processor.processGIF(data)
+	}
+
+	return processor.processImage(data, format)
+}
+
+// fit returns the dimensions of the image scaled into the constraints' box
+// keeping its aspect ratio, additionally scaled by the specified ratio.
+func (processor *ImageProcessor) fit(width, height int, scale float64) (fittedWidth, fittedHeight int) {
+	ratio := 1.0
+	if processor.Constraints.MaxWidth != 0 {
+		ratio = math.Min(ratio, float64(processor.Constraints.MaxWidth)/float64(width))
+	}
+
+	if processor.Constraints.MaxHeight != 0 {
+		ratio = math.Min(ratio, float64(processor.Constraints.MaxHeight)/float64(height))
+	}
+
+	ratio *= scale
+	fittedWidth = int(math.Max(1.0, math.Round(float64(width)*ratio)))
+	fittedHeight = int(math.Max(1.0, math.Round(float64(height)*ratio)))
+
+	return fittedWidth, fittedHeight
+}
+
+// isFitting returns whether the encoded image data is within the maximum file
+// size.
+func (processor *ImageProcessor) isFitting(data []byte) (isFitting bool) {
+	return processor.Constraints.MaxFileSize == 0 ||
+		int64(len(data)) <= processor.Constraints.MaxFileSize
+}
+
+// processGIF scales every frame of the animated GIF image, composing the
+// frames first so the scaled frames are independent of their disposal, and
+// draws them with a palette shared by every composed frame.
+func (processor *ImageProcessor) processGIF(data []byte) (processedData []byte, err error) {
+	animation, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrapf(ErrorInvalidEmojiImage, "GIF image is corrupt (%s)", err.Error())
+	}
+
+	frames, delays := composeGIFFrames(animation, processor.Constraints.MaxFrameCount)
+	framePalette := sharedGIFPalette(animation)
+
+	for scale := 1.0; ; scale *= imageProcessorScaleStep {
+		width, height := processor.fit(animation.Config.Width, animation.Config.Height, scale)
+		processed := &gif.GIF{
+			Delay:     delays,
+			Disposal:  make([]byte, 0, len(frames)),
+			Image:     make([]*image.Paletted, 0, len(frames)),
+			LoopCount: animation.LoopCount,
+		}
+
+		for _, frame := range frames {
+			scaled := processor.render(frame, width, height, color.Transparent)
+			paletted := image.NewPaletted(scaled.Bounds(), framePalette)
+			xdraw.Draw(paletted, paletted.Bounds(), scaled, scaled.Bounds().Min, xdraw.Src)
+
+			processed.Disposal = append(processed.Disposal, gif.DisposalBackground)
+			processed.Image = append(processed.Image, paletted)
+		}
+
+		buffer := bytes.Buffer{}
+		err = gif.EncodeAll(&buffer, processed)
+		if err != nil {
+			return nil, errors.Wrapf(err, "encoding GIF image failed, width: '%+v', height: '%+v'", width, height)
+		}
+
+		if processor.isFitting(buffer.Bytes()) {
+			return buffer.Bytes(), nil
+		} else if width <= imageProcessorMinDimension &&
+			height <= imageProcessorMinDimension {
+			return nil, errors.Wrapf(ErrorInvalidEmojiImage, "GIF image could not be compressed under %d B", processor.Constraints.MaxFileSize)
+		}
+	}
+}
+
+// processImage scales and recompresses the static PNG or JPEG image.
+func (processor *ImageProcessor) processImage(data []byte, format string) (processedData []byte, err error) {
+	source, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrapf(ErrorInvalidEmojiImage, "%s image is corrupt (%s)", format, err.Error())
+	}
+
+	background := color.Color(color.Transparent)
+	if format == "jpeg" {
+		background = color.White
+	}
+
+	for scale := 1.0; ; scale *= imageProcessorScaleStep {
+		width, height := processor.fit(source.Bounds().Dx(), source.Bounds().Dy(), scale)
+		scaled := processor.render(source, width, height, background)
+
+		buffer := bytes.Buffer{}
+		if format == "jpeg" {
+			for _, quality := range imageProcessorJPEGQualities {
+				buffer.Reset()
+
+				err = jpeg.Encode(&buffer, scaled, &jpeg.Options{Quality: quality})
+				if err != nil {
+					return nil, errors.Wrapf(err, "encoding JPEG image failed, width: '%+v', height: '%+v', quality: '%+v'", width, height, quality)
+				} else if processor.isFitting(buffer.Bytes()) {
+					break
+				}
+			}
+		} else {
+			encoder := png.Encoder{
+				CompressionLevel: png.BestCompression,
+			}
+
+			err = encoder.Encode(&buffer, scaled)
+			if err != nil {
+				return nil, errors.Wrapf(err, "encoding PNG image failed, width: '%+v', height: '%+v'", width, height)
+			}
+		}
+
+		if processor.isFitting(buffer.Bytes()) {
+			return buffer.Bytes(), nil
+		} else if width <= imageProcessorMinDimension &&
+			height <= imageProcessorMinDimension {
+			return nil, errors.Wrapf(ErrorInvalidEmojiImage, "%s image could not be compressed under %d B", format, processor.Constraints.MaxFileSize)
+		}
+	}
+}
+
+// render draws the source image scaled to the specified dimensions onto a
+// canvas filled with the background, centered on a square canvas when padding.
+func (processor *ImageProcessor) render(source image.Image, width, height int, background color.Color) (canvas *image.NRGBA) {
+	canvasWidth, canvasHeight := width, height
+	if processor.IsPaddingSquare {
+		canvasWidth = int(math.Max(float64(width), float64(height)))
+		canvasHeight = canvasWidth
+	}
+
+	canvas = image.NewNRGBA(image.Rect(0, 0, canvasWidth, canvasHeight))
+	xdraw.Draw(canvas, canvas.Bounds(), image.NewUniform(background), image.Point{}, xdraw.Src)
+
+	offsetX, offsetY := (canvasWidth-width)/2, (canvasHeight-height)/2
+	target := image.Rect(offsetX, offsetY, offsetX+width, offsetY+height)
+	xdraw.CatmullRom.Scale(canvas, target, source, source.Bounds(), xdraw.Over, nil)
+
+	return canvas
+}
+
+// composeGIFFrames returns the fully composed frames of the animation with
+// their delays, merging consecutive frames to keep at most the maximum number
+// of frames when it is set.
+func composeGIFFrames(animation *gif.GIF, maxFrameCount int) (frames []image.Image, delays []int) {
+	step := 1
+	if maxFrameCount != 0 &&
+		len(animation.Image) > maxFrameCount {
+		step = int(math.Ceil(float64(len(animation.Image)) / float64(maxFrameCount)))
+	}
+
+	bounds := image.Rect(0, 0, animation.Config.Width, animation.Config.Height)
+	canvas := image.NewNRGBA(bounds)
+	for frameIndex, frame := range animation.Image {
+		previous := image.NewNRGBA(bounds)
+		xdraw.Draw(previous, bounds, canvas, image.Point{}, xdraw.Src)
+		xdraw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, xdraw.Over)
+
+		if frameIndex%step == 0 {
+			composed := image.NewNRGBA(bounds)
+			xdraw.Draw(composed, bounds, canvas, image.Point{}, xdraw.Src)
+			frames = append(frames, composed)
+			delays = append(delays, 0)
+		}
+
+		if frameIndex < len(animation.Delay) {
+			delays[len(delays)-1] += animation.Delay[frameIndex]
+		}
+
+		if frameIndex < len(animation.Disposal) {
+			switch animation.Disposal[frameIndex] {
+			case gif.DisposalBackground:
+				xdraw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, xdraw.Src)
+			case gif.DisposalPrevious:
+				canvas = previous
+			}
+		}
+	}
+
+	return frames, delays
+}
+
+// sharedGIFPalette returns a palette for every composed frame of the
+// animation, the opaque colors of the source frames' palettes used by the
+// most pixels, at most 255 of them, followed by a transparent color. The
+// palette is built from every frame instead of reusing a frame's local
+// palette, because a composed frame may combine the colors of several source
+// frames' local palettes.
+func sharedGIFPalette(animation *gif.GIF) (palette color.Palette) {
+	pixelCounts := make(map[color.NRGBA]int)
+	for _, frame := range animation.Image {
+		for _, colorIndex := range frame.Pix {
+			if int(colorIndex) >= len(frame.Palette) {
+				continue
+			}
+
+			pixelColor := color.NRGBAModel.Convert(frame.Palette[colorIndex]).(color.NRGBA)
+			if pixelColor.A != 0 {
+				pixelCounts[pixelColor]++
+			}
+		}
+	}
+
+	colors := make([]color.NRGBA, 0, len(pixelCounts))
+	for pixelColor := range pixelCounts {
+		colors = append(colors, pixelColor)
+	}
+	sort.Slice(colors, func(firstIndex, secondIndex int) (isLess bool) {
+		first, second := colors[firstIndex], colors[secondIndex]
+		if pixelCounts[first] != pixelCounts[second] {
+			return pixelCounts[first] > pixelCounts[second]
+		}
+
+		return uint32(first.R)<<24|uint32(first.G)<<16|uint32(first.B)<<8|uint32(first.A) <
+			uint32(second.R)<<24|uint32(second.G)<<16|uint32(second.B)<<8|uint32(second.A)
+	})
+
+	if len(colors) > 255 {
+		colors = colors[:255]
+	}
+
+	palette = make(color.Palette, 0, len(colors)+1)
+	for _, paletteColor := range colors {
+		palette = append(palette, paletteColor)
+	}
+
+	return append(palette, color.Transparent)
+}
+
+// emojiFileData returns the content of the emoji file processed by the
+// client's image processor when it is set.
+func (client *Client) emojiFileData(emojiPath string) (data []byte, err error) {
+	data, err = ioutil.ReadFile(emojiPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading emoji file failed, path: '%+v'", emojiPath)
+	}
+
+	return client.ImageProcessor.Process(data)
+}
+
+// validateEmojiFile checks the emoji file as it would be uploaded against the
+// image constraints.
+func (client *Client) validateEmojiFile(emojiPath string, imageConstraints *ImageConstraints) (err error) {
+	if imageConstraints == nil {
+		return nil
+	}
+
+	data, err := client.emojiFileData(emojiPath)
+	if err != nil {
+		return err
+	}
+
+	return imageConstraints.ValidateData(data)
+}
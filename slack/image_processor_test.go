@@ -0,0 +1,165 @@
+package slack_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+)
+
+var (
+	blue   = color.RGBA{B: 255, A: 255}
+	green  = color.RGBA{G: 255, A: 255}
+	red    = color.RGBA{R: 255, A: 255}
+	yellow = color.RGBA{R: 255, G: 255, A: 255}
+)
+
+func TestImageProcessorProcessGIFComposedColors(t *testing.T) {
+	redFrame := newGIFFrame(image.Rect(0, 0, 16, 16), red)
+	greenFrame := newGIFFrame(image.Rect(0, 0, 8, 16), green)
+	data := encodeGIF(t, &gif.GIF{
+		Delay:    []int{10, 10},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalNone},
+		Image:    []*image.Paletted{redFrame, greenFrame},
+	})
+
+	processor := &slack.ImageProcessor{
+		Constraints: slack.ImageConstraints{
+			MaxHeight: 8,
+			MaxWidth:  8,
+		},
+	}
+	processedData, err := processor.Process(data)
+	if err != nil {
+		t.Fatalf("processing GIF failed, error: '%+v'", err)
+	}
+
+	animation := decodeGIF(t, processedData)
+	if len(animation.Image) != 2 {
+		t.Fatalf("frame count mismatches, expected: '%+v', actual: '%+v'", 2, len(animation.Image))
+	}
+
+	assertColor(t, animation.Image[1], 1, 4, green)
+	assertColor(t, animation.Image[1], 6, 4, red)
+}
+
+func TestImageProcessorProcessGIFLocalPalettes(t *testing.T) {
+	bounds := image.Rect(0, 0, 16, 16)
+	firstFrame := image.NewPaletted(bounds, color.Palette{red, green})
+	secondFrame := image.NewPaletted(bounds, color.Palette{blue, yellow})
+	thirdFrame := image.NewPaletted(bounds, color.Palette{green, blue})
+	for _, frame := range []*image.Paletted{firstFrame, secondFrame, thirdFrame} {
+		for y := 0; y < 16; y++ {
+			for x := 8; x < 16; x++ {
+				frame.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+
+	data := encodeGIF(t, &gif.GIF{
+		Delay:    []int{10, 10, 10},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalNone, gif.DisposalNone},
+		Image:    []*image.Paletted{firstFrame, secondFrame, thirdFrame},
+	})
+
+	processor := &slack.ImageProcessor{
+		Constraints: slack.ImageConstraints{
+			MaxHeight: 8,
+			MaxWidth:  8,
+		},
+	}
+	processedData, err := processor.Process(data)
+	if err != nil {
+		t.Fatalf("processing GIF failed, error: '%+v'", err)
+	}
+
+	animation := decodeGIF(t, processedData)
+	if len(animation.Image) != 3 {
+		t.Fatalf("frame count mismatches, expected: '%+v', actual: '%+v'", 3, len(animation.Image))
+	}
+
+	assertColor(t, animation.Image[0], 1, 4, red)
+	assertColor(t, animation.Image[0], 6, 4, green)
+	assertColor(t, animation.Image[1], 1, 4, blue)
+	assertColor(t, animation.Image[1], 6, 4, yellow)
+	assertColor(t, animation.Image[2], 1, 4, green)
+	assertColor(t, animation.Image[2], 6, 4, blue)
+}
+
+func TestImageProcessorProcessGIFSubsampledFrames(t *testing.T) {
+	bounds := image.Rect(0, 0, 8, 8)
+	data := encodeGIF(t, &gif.GIF{
+		Delay: []int{10, 10, 10, 10},
+		Image: []*image.Paletted{
+			newGIFFrame(bounds, red),
+			newGIFFrame(bounds, green),
+			newGIFFrame(bounds, blue),
+			newGIFFrame(bounds, yellow),
+		},
+	})
+
+	processor := &slack.ImageProcessor{
+		Constraints: slack.ImageConstraints{
+			MaxFrameCount: 2,
+		},
+	}
+	processedData, err := processor.Process(data)
+	if err != nil {
+		t.Fatalf("processing GIF failed, error: '%+v'", err)
+	}
+
+	animation := decodeGIF(t, processedData)
+	if len(animation.Image) != 2 {
+		t.Fatalf("frame count mismatches, expected: '%+v', actual: '%+v'", 2, len(animation.Image))
+	} else if animation.Delay[0] != 20 ||
+		animation.Delay[1] != 20 {
+		t.Errorf("merged frame delays mismatch, expected: '%+v', actual: '%+v'", []int{20, 20}, animation.Delay)
+	}
+
+	assertColor(t, animation.Image[0], 4, 4, red)
+	assertColor(t, animation.Image[1], 4, 4, blue)
+}
+
+// assertColor checks the color of the frame's pixel.
+func assertColor(t *testing.T, frame *image.Paletted, x, y int, expectedColor color.Color) {
+	t.Helper()
+
+	actualColor := color.RGBAModel.Convert(frame.At(x, y))
+	if actualColor != color.RGBAModel.Convert(expectedColor) {
+		t.Errorf("pixel color mismatches, x: '%+v', y: '%+v', expected: '%+v', actual: '%+v'", x, y, expectedColor, actualColor)
+	}
+}
+
+// decodeGIF returns the decoded animated GIF image.
+func decodeGIF(t *testing.T, data []byte) (animation *gif.GIF) {
+	t.Helper()
+
+	animation, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decoding GIF failed, error: '%+v'", err)
+	}
+
+	return animation
+}
+
+// encodeGIF returns the encoded animated GIF image.
+func encodeGIF(t *testing.T, animation *gif.GIF) (data []byte) {
+	t.Helper()
+
+	buffer := bytes.Buffer{}
+	err := gif.EncodeAll(&buffer, animation)
+	if err != nil {
+		t.Fatalf("encoding GIF failed, error: '%+v'", err)
+	}
+
+	return buffer.Bytes()
+}
+
+// newGIFFrame returns a frame of the bounds filled with a single color of its
+// own local palette.
+func newGIFFrame(bounds image.Rectangle, frameColor color.Color) (frame *image.Paletted) {
+	return image.NewPaletted(bounds, color.Palette{frameColor})
+}
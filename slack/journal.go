@@ -0,0 +1,232 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// UploadOutcome describes the result of uploading a single emoji file.
+type UploadOutcome string
+
+const (
+	// UploadOutcomeFailed marks an emoji file which could not be uploaded.
+	UploadOutcomeFailed UploadOutcome = "failed"
+
+	// UploadOutcomeInvalid marks an emoji file skipped because it violates
+	// the image constraints.
+	UploadOutcomeInvalid UploadOutcome = "invalid"
+
+	// UploadOutcomeNameTaken marks an emoji file uploaded under its taken name
+	// because its name was taken by a non-custom emoji.
+	UploadOutcomeNameTaken UploadOutcome = "name-taken"
+
+	// UploadOutcomeReplaced marks an emoji file uploaded again after deleting
+	// its existing emoji with a differing image.
+	UploadOutcomeReplaced UploadOutcome = "replaced"
+
+	// UploadOutcomeSkipped marks an emoji file skipped because its emoji
+	// already existed.
+	UploadOutcomeSkipped UploadOutcome = "skipped"
+
+	// UploadOutcomeUploaded marks an emoji file uploaded under its name.
+	UploadOutcomeUploaded UploadOutcome = "uploaded"
+)
+
+// JournalEntry records the outcome of uploading a single emoji file.
+type JournalEntry struct {
+	Error   string        `json:"error,omitempty"`
+	Name    string        `json:"name"`
+	Outcome UploadOutcome `json:"outcome"`
+	Path    string        `json:"path"`
+	Time    time.Time     `json:"time"`
+}
+
+// Journal persists the upload outcome of every emoji file as JSON lines, so
+// an aborted bulk upload can be resumed where it stopped.
+type Journal struct {
+	entries map[string]JournalEntry
+	file    *os.File
+	mutex   sync.Mutex
+	path    string
+}
+
+// LoadJournal loads the entries of the journal file at the specified path
+// read-only, e.g. to plan resuming a dry run without modifying the journal.
+// Recording entries into the loaded journal fails.
+func LoadJournal(journalPath string) (journal *Journal, err error) {
+	if journalPath == "" {
+		return nil, fmt.Errorf("journal path is empty")
+	}
+
+	journal = &Journal{
+		entries: make(map[string]JournalEntry),
+		path:    journalPath,
+	}
+
+	err = journal.load()
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading journal failed, path: '%+v'", journalPath)
+	}
+
+	return journal, nil
+}
+
+// OpenJournal opens the journal file at the specified path, loading its
+// entries when resuming and truncating it otherwise.
+func OpenJournal(journalPath string, isResuming bool) (journal *Journal, err error) {
+	if journalPath == "" {
+		return nil, fmt.Errorf("journal path is empty")
+	}
+
+	journal = &Journal{
+		entries: make(map[string]JournalEntry),
+		path:    journalPath,
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if isResuming {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+
+		err = journal.load()
+		if err != nil {
+			return nil, errors.Wrapf(err, "loading journal failed, path: '%+v'", journalPath)
+		}
+	}
+
+	journal.file, err = os.OpenFile(journalPath, flags, 0644)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening journal file failed, path: '%+v'", journalPath)
+	}
+
+	return journal, nil
+}
+
+// Close closes the underlying journal file.
+func (journal *Journal) Close() (err error) {
+	if journal == nil ||
+		journal.file == nil {
+		return nil
+	}
+
+	journal.mutex.Lock()
+	defer journal.mutex.Unlock()
+
+	err = journal.file.Close()
+	if err != nil {
+		return errors.Wrapf(err, "closing journal file failed, path: '%+v'", journal.path)
+	}
+
+	return nil
+}
+
+// Entry returns the latest journal entry of the specified emoji file path.
+func (journal *Journal) Entry(emojiPath string) (entry JournalEntry, isExisting bool) {
+	if journal == nil {
+		return JournalEntry{}, false
+	}
+
+	journal.mutex.Lock()
+	defer journal.mutex.Unlock()
+
+	entry, isExisting = journal.entries[journalKey(emojiPath)]
+
+	return entry, isExisting
+}
+
+// IsCompleted returns whether the specified emoji file path has a journaled
+// outcome which does not need to be retried, failed and invalid files are
+// retried as they may have been fixed since.
+func (journal *Journal) IsCompleted(emojiPath string) (isCompleted bool) {
+	entry, isExisting := journal.Entry(emojiPath)
+
+	return isExisting &&
+		entry.Outcome != UploadOutcomeFailed &&
+		entry.Outcome != UploadOutcomeInvalid
+}
+
+// Record appends the entry to the journal file and syncs it to the disk.
+func (journal *Journal) Record(entry JournalEntry) (err error) {
+	if journal == nil {
+		return nil
+	}
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrapf(err, "marshalling journal entry failed, entry: '%+v'", entry)
+	}
+
+	journal.mutex.Lock()
+	defer journal.mutex.Unlock()
+
+	if journal.file == nil {
+		return fmt.Errorf("journal is loaded read-only, path: '%+v'", journal.path)
+	}
+
+	_, err = journal.file.Write(append(line, '\n'))
+	if err != nil {
+		return errors.Wrapf(err, "writing journal entry failed, path: '%+v', entry: '%+v'", journal.path, entry)
+	}
+
+	err = journal.file.Sync()
+	if err != nil {
+		return errors.Wrapf(err, "syncing journal file failed, path: '%+v'", journal.path)
+	}
+
+	journal.entries[journalKey(entry.Path)] = entry
+
+	return nil
+}
+
+// load reads the existing entries of the journal file, ignoring a truncated
+// last line left behind by an interrupted write.
+func (journal *Journal) load() (err error) {
+	data, err := ioutil.ReadFile(journal.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "reading journal file failed, path: '%+v'", journal.path)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	for lineIndex, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+
+		entry := JournalEntry{}
+		err = json.Unmarshal(line, &entry)
+		if err != nil &&
+			lineIndex == len(lines)-1 {
+			break
+		} else if err != nil {
+			return errors.Wrapf(err, "unmarshalling journal entry failed, line number: '%+v', raw line: '%+v'", lineIndex+1, string(line))
+		}
+
+		journal.entries[journalKey(entry.Path)] = entry
+	}
+
+	return nil
+}
+
+// journalKey returns the key identifying an emoji file path independently of
+// the working directory.
+func journalKey(emojiPath string) (key string) {
+	absolutePath, err := filepath.Abs(emojiPath)
+	if err != nil {
+		return filepath.Clean(emojiPath)
+	}
+
+	return absolutePath
+}
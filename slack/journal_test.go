@@ -0,0 +1,125 @@
+package slack_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/color"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+	"github.com/pregnor/slack-emoji-upload/slack/slacktest"
+)
+
+func TestLoadJournal(t *testing.T) {
+	directoryPath, removeDirectory := newTempDirectory(t)
+	defer removeDirectory()
+
+	journalPath := filepath.Join(directoryPath, "journal.jsonl")
+	journal, err := slack.OpenJournal(journalPath, false)
+	if err != nil {
+		t.Fatalf("opening journal failed, error: '%+v'", err)
+	}
+
+	entries := []slack.JournalEntry{
+		{Name: "done", Outcome: slack.UploadOutcomeUploaded, Path: filepath.Join(directoryPath, "done.png")},
+		{Name: "failed", Outcome: slack.UploadOutcomeFailed, Path: filepath.Join(directoryPath, "failed.png")},
+	}
+	for _, entry := range entries {
+		err = journal.Record(entry)
+		if err != nil {
+			t.Fatalf("recording journal entry failed, error: '%+v'", err)
+		}
+	}
+	_ = journal.Close()
+
+	data, _ := ioutil.ReadFile(journalPath)
+
+	loadedJournal, err := slack.LoadJournal(journalPath)
+	if err != nil {
+		t.Fatalf("loading journal failed, error: '%+v'", err)
+	}
+
+	if !loadedJournal.IsCompleted(entries[0].Path) {
+		t.Errorf("uploaded entry is not completed")
+	} else if loadedJournal.IsCompleted(entries[1].Path) {
+		t.Errorf("failed entry is completed")
+	}
+
+	err = loadedJournal.Record(slack.JournalEntry{Name: "new", Outcome: slack.UploadOutcomeUploaded, Path: "new.png"})
+	if err == nil {
+		t.Errorf("recording into read-only journal succeeded")
+	}
+
+	err = loadedJournal.Close()
+	if err != nil {
+		t.Errorf("closing read-only journal failed, error: '%+v'", err)
+	}
+
+	if loadedData, _ := ioutil.ReadFile(journalPath); !bytes.Equal(data, loadedData) {
+		t.Errorf("loading journal modified it, before: '%s', after: '%s'", data, loadedData)
+	}
+}
+
+func TestPostEmojisWithOptionsResumeDryRun(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	client := newTestClient(t, server)
+
+	directoryPath, removeDirectory := newTempDirectory(t)
+	defer removeDirectory()
+	emojiPath := filepath.Join(directoryPath, "done.png")
+	writeFile(t, emojiPath, newPNG(t, color.White))
+	writeFile(t, filepath.Join(directoryPath, "pending.png"), newPNG(t, color.Black))
+
+	journalDirectoryPath, removeJournalDirectory := newTempDirectory(t)
+	defer removeJournalDirectory()
+	journalPath := filepath.Join(journalDirectoryPath, "journal.jsonl")
+	journal, err := slack.OpenJournal(journalPath, false)
+	if err != nil {
+		t.Fatalf("opening journal failed, error: '%+v'", err)
+	}
+	_ = journal.Record(slack.JournalEntry{Name: "done", Outcome: slack.UploadOutcomeUploaded, Path: emojiPath})
+	_ = journal.Close()
+
+	loadedJournal, err := slack.LoadJournal(journalPath)
+	if err != nil {
+		t.Fatalf("loading journal failed, error: '%+v'", err)
+	}
+
+	buffer := bytes.Buffer{}
+	err = client.PostEmojisWithOptions(directoryPath, "", "", "", "-2", slack.UploadOptions{
+		DryRun: &slack.DryRun{
+			Format: slack.PlanFormatJSON,
+			Writer: &buffer,
+		},
+		Journal: loadedJournal,
+	})
+	if err != nil {
+		t.Fatalf("planning emoji uploads failed, error: '%+v'", err)
+	}
+
+	plan := slack.Plan{}
+	err = json.Unmarshal(buffer.Bytes(), &plan)
+	if err != nil {
+		t.Fatalf("unmarshalling plan failed, error: '%+v', raw plan: '%s'", err, buffer.String())
+	}
+
+	items := make(map[string]slack.PlanItem, len(plan.Items))
+	for _, item := range plan.Items {
+		items[item.Name] = item
+	}
+
+	if item := items["done"]; item.Action != slack.PlanActionSkip ||
+		item.Reason != "journaled as completed" {
+		t.Errorf("journaled file is not skipped, item: '%+v'", item)
+	} else if item := items["pending"]; item.Action != slack.PlanActionAdd {
+		t.Errorf("pending file is not added, item: '%+v'", item)
+	}
+
+	if emojis := server.Emojis(); len(emojis) != 0 {
+		t.Errorf("dry run uploaded emojis, emojis: '%+v'", emojis)
+	}
+}
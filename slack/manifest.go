@@ -0,0 +1,157 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ManifestFileName is the name of the manifest file describing the emojis of
+// a backup directory.
+const ManifestFileName = "manifest.json"
+
+// ManifestEmoji describes a single backed up emoji, either an image emoji
+// stored in a file or an alias of another emoji.
+type ManifestEmoji struct {
+	AliasFor        string   `json:"alias_for,omitempty"`
+	Aliases         []string `json:"aliases,omitempty"`
+	Created         int64    `json:"created"`
+	FileName        string   `json:"file_name,omitempty"`
+	Name            string   `json:"name"`
+	UserDisplayName string   `json:"user_display_name,omitempty"`
+	UserID          string   `json:"user_id,omitempty"`
+}
+
+// IsAlias returns whether the manifest emoji is an alias of another emoji.
+func (emoji ManifestEmoji) IsAlias() (isAlias bool) {
+	return emoji.AliasFor != ""
+}
+
+// Manifest describes the emojis of a backup directory.
+type Manifest struct {
+	Created  time.Time       `json:"created"`
+	Emojis   []ManifestEmoji `json:"emojis"`
+	TeamName string          `json:"team_name"`
+}
+
+// NewManifest instantiates a manifest of the specified emojis by name,
+// collecting the aliases of every image emoji.
+func NewManifest(teamName string, emojis map[string]Emoji) (manifest *Manifest) {
+	aliases := make(map[string][]string)
+	for name, emoji := range emojis {
+		if emoji.IsAliasEmoji() {
+			aliases[emoji.AliasFor] = append(aliases[emoji.AliasFor], name)
+		}
+	}
+
+	manifest = &Manifest{
+		Created:  time.Now().UTC(),
+		Emojis:   make([]ManifestEmoji, 0, len(emojis)),
+		TeamName: teamName,
+	}
+	for name, emoji := range emojis {
+		manifestEmoji := ManifestEmoji{
+			Created:         emoji.Created,
+			Name:            name,
+			UserDisplayName: emoji.UserDisplayName,
+			UserID:          emoji.UserID,
+		}
+
+		if emoji.IsAliasEmoji() {
+			manifestEmoji.AliasFor = emoji.AliasFor
+		} else {
+			manifestEmoji.Aliases = aliases[name]
+			sort.Strings(manifestEmoji.Aliases)
+		}
+
+		manifest.Emojis = append(manifest.Emojis, manifestEmoji)
+	}
+
+	sort.Slice(manifest.Emojis, func(firstIndex, secondIndex int) (isLess bool) {
+		return manifest.Emojis[firstIndex].Name < manifest.Emojis[secondIndex].Name
+	})
+
+	return manifest
+}
+
+// NewManifestFromFile instantiates a manifest read from the JSON manifest file
+// at the specified path.
+func NewManifestFromFile(manifestPath string) (manifest *Manifest, err error) {
+	if manifestPath == "" {
+		return nil, fmt.Errorf("manifest path is empty")
+	}
+
+	manifestData, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading manifest file failed, path: '%+v'", manifestPath)
+	}
+
+	err = json.Unmarshal(manifestData, &manifest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling manifest JSON failed, path: '%+v'", manifestPath)
+	}
+
+	return manifest, nil
+}
+
+// WriteFile writes the manifest as JSON to the specified path, replacing the
+// previous file atomically.
+func (manifest *Manifest) WriteFile(manifestPath string) (err error) {
+	if manifest == nil {
+		return fmt.Errorf("manifest is nil")
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "marshalling manifest JSON failed, manifest: '%+v'", manifest)
+	}
+
+	err = writeFileAtomically(manifestPath, append(manifestData, '\n'), 0644)
+	if err != nil {
+		return errors.Wrapf(err, "writing manifest file failed, path: '%+v'", manifestPath)
+	}
+
+	return nil
+}
+
+// writeFileAtomically writes the data into a temporary file of the specified
+// mode next to the specified path and renames it into place.
+func writeFileAtomically(path string, data []byte, mode os.FileMode) (err error) {
+	temporaryFile, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return errors.Wrapf(err, "creating temporary file failed, path: '%+v'", path)
+	}
+	defer func() { _ = os.Remove(temporaryFile.Name()) }()
+
+	err = temporaryFile.Chmod(mode)
+	if err != nil {
+		_ = temporaryFile.Close()
+
+		return errors.Wrapf(err, "changing temporary file mode failed, path: '%+v'", temporaryFile.Name())
+	}
+
+	_, err = temporaryFile.Write(data)
+	if err != nil {
+		_ = temporaryFile.Close()
+
+		return errors.Wrapf(err, "writing temporary file failed, path: '%+v'", temporaryFile.Name())
+	}
+
+	err = temporaryFile.Close()
+	if err != nil {
+		return errors.Wrapf(err, "closing temporary file failed, path: '%+v'", temporaryFile.Name())
+	}
+
+	err = os.Rename(temporaryFile.Name(), path)
+	if err != nil {
+		return errors.Wrapf(err, "renaming temporary file failed, temporary path: '%+v', path: '%+v'", temporaryFile.Name(), path)
+	}
+
+	return nil
+}
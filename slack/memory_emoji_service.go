@@ -0,0 +1,221 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MemoryEmojiService is an in-memory emoji service for tests and dry runs,
+// failing with the same errors as the client.
+type MemoryEmojiService struct {
+	emojis        map[string]Emoji
+	images        map[string][]byte
+	mutex         sync.RWMutex
+	reservedNames map[string]bool
+}
+
+// NewMemoryEmojiService instantiates an in-memory emoji service holding the
+// specified emojis, e.g. the known emojis of a client to dry run against.
+func NewMemoryEmojiService(emojis map[string]Emoji) (service *MemoryEmojiService) {
+	service = &MemoryEmojiService{
+		emojis:        make(map[string]Emoji, len(emojis)),
+		images:        make(map[string][]byte),
+		reservedNames: make(map[string]bool),
+	}
+	for name, emoji := range emojis {
+		service.emojis[name] = emoji
+	}
+
+	return service
+}
+
+// DeleteEmojiContext removes the emoji of the name with its aliases like
+// Slack does.
+func (service *MemoryEmojiService) DeleteEmojiContext(ctx context.Context, emojiName string) (err error) {
+	if service == nil {
+		return fmt.Errorf("service is nil")
+	} else if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if _, isExisting := service.emojis[emojiName]; !isExisting {
+		return ErrorEmojiDoesNotExist
+	}
+
+	delete(service.emojis, emojiName)
+	delete(service.images, emojiName)
+	for name, emoji := range service.emojis {
+		if emoji.AliasFor == emojiName {
+			delete(service.emojis, name)
+		}
+	}
+
+	return nil
+}
+
+// GetEmojisContext lists the custom emojis by name.
+func (service *MemoryEmojiService) GetEmojisContext(ctx context.Context) (emojis map[string]Emoji, err error) {
+	if service == nil {
+		return nil, fmt.Errorf("service is nil")
+	} else if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	emojis = make(map[string]Emoji, len(service.emojis))
+	for name, emoji := range service.emojis {
+		emojis[name] = emoji
+	}
+
+	return emojis, nil
+}
+
+// Image returns the image data the emoji of the name was added with.
+func (service *MemoryEmojiService) Image(emojiName string) (data []byte, isExisting bool) {
+	if service == nil {
+		return nil, false
+	}
+
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	data, isExisting = service.images[emojiName]
+
+	return data, isExisting
+}
+
+// PostAliasContext adds an alias under the name to an existing custom or
+// reserved built-in emoji, aliases of aliases standing for the aliased emoji
+// and other targets failing with ErrorInvalidAlias like on Slack.
+func (service *MemoryEmojiService) PostAliasContext(ctx context.Context, aliasName, targetName string) (err error) {
+	if service == nil {
+		return fmt.Errorf("service is nil")
+	} else if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	err = service.checkNewName(aliasName)
+	if err != nil {
+		return err
+	}
+
+	target, isExisting := service.emojis[targetName]
+	if !isExisting &&
+		!service.reservedNames[targetName] {
+		return ErrorInvalidAlias
+	} else if target.IsAliasEmoji() {
+		targetName = target.AliasFor
+	}
+
+	service.emojis[aliasName] = Emoji{
+		AliasFor: targetName,
+		Created:  time.Now().Unix(),
+		IsAlias:  1,
+		Name:     aliasName,
+	}
+
+	return nil
+}
+
+// PostEmojiDataContext adds an emoji of the image data under the name.
+func (service *MemoryEmojiService) PostEmojiDataContext(ctx context.Context, emojiName, fileName string, data []byte) (err error) {
+	if service == nil {
+		return fmt.Errorf("service is nil")
+	} else if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	err = service.checkNewName(emojiName)
+	if err != nil {
+		return err
+	}
+
+	service.emojis[emojiName] = Emoji{
+		Created: time.Now().Unix(),
+		Name:    emojiName,
+	}
+	service.images[emojiName] = append([]byte(nil), data...)
+
+	return nil
+}
+
+// RenameEmojiContext renames an existing emoji, moving its aliases along.
+func (service *MemoryEmojiService) RenameEmojiContext(ctx context.Context, emojiName, newName string) (err error) {
+	if service == nil {
+		return fmt.Errorf("service is nil")
+	} else if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	emoji, isExisting := service.emojis[emojiName]
+	if !isExisting {
+		return ErrorEmojiDoesNotExist
+	}
+
+	err = service.checkNewName(newName)
+	if err != nil {
+		return err
+	}
+
+	delete(service.emojis, emojiName)
+	emoji.Name = newName
+	service.emojis[newName] = emoji
+
+	if data, isExisting := service.images[emojiName]; isExisting {
+		delete(service.images, emojiName)
+		service.images[newName] = data
+	}
+
+	for name, alias := range service.emojis {
+		if alias.AliasFor == emojiName {
+			alias.AliasFor = newName
+			service.emojis[name] = alias
+		}
+	}
+
+	return nil
+}
+
+// ReserveNames marks the names as taken by non-custom emojis, failing the
+// additions under them with ErrorEmojiNameTaken and accepting them as alias
+// targets.
+func (service *MemoryEmojiService) ReserveNames(names ...string) {
+	if service == nil {
+		return
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	for _, name := range names {
+		service.reservedNames[name] = true
+	}
+}
+
+// checkNewName returns the error of adding an emoji under the name without
+// locking the service state.
+func (service *MemoryEmojiService) checkNewName(name string) (err error) {
+	if _, isExisting := service.emojis[name]; isExisting {
+		return ErrorEmojiExists
+	} else if service.reservedNames[name] {
+		return ErrorEmojiNameTaken
+	}
+
+	return nil
+}
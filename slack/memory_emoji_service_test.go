@@ -0,0 +1,156 @@
+package slack_test
+
+import (
+	"context"
+	"image/color"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+	"github.com/pregnor/slack-emoji-upload/slack/slacktest"
+)
+
+func TestEmojiServiceOperations(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.ReserveNames("thumbsup")
+	memoryService := slack.NewMemoryEmojiService(nil)
+	memoryService.ReserveNames("thumbsup")
+
+	testCases := []struct {
+		caseDescription string
+		service         slack.EmojiService
+	}{
+		{
+			caseDescription: "client",
+			service:         newTestClient(t, server),
+		},
+		{
+			caseDescription: "memory service",
+			service:         memoryService,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			ctx := context.Background()
+			data := newPNG(t, color.White)
+			steps := []struct {
+				expectedError error
+				operation     func() (err error)
+				stepName      string
+			}{
+				{
+					operation: func() (err error) { return testCase.service.PostEmojiDataContext(ctx, "party", "party.png", data) },
+					stepName:  "adding emoji",
+				},
+				{
+					expectedError: slack.ErrorEmojiExists,
+					operation:     func() (err error) { return testCase.service.PostEmojiDataContext(ctx, "party", "party.png", data) },
+					stepName:      "adding existing emoji",
+				},
+				{
+					expectedError: slack.ErrorEmojiNameTaken,
+					operation: func() (err error) {
+						return testCase.service.PostEmojiDataContext(ctx, "thumbsup", "thumbsup.png", data)
+					},
+					stepName: "adding emoji under built-in name",
+				},
+				{
+					operation: func() (err error) { return testCase.service.PostAliasContext(ctx, "party-alias", "party") },
+					stepName:  "adding alias",
+				},
+				{
+					operation: func() (err error) { return testCase.service.PostAliasContext(ctx, "yes", "thumbsup") },
+					stepName:  "adding alias of built-in emoji",
+				},
+				{
+					expectedError: slack.ErrorInvalidAlias,
+					operation:     func() (err error) { return testCase.service.PostAliasContext(ctx, "nope", "missing") },
+					stepName:      "adding alias of missing emoji",
+				},
+				{
+					operation: func() (err error) { return testCase.service.DeleteEmojiContext(ctx, "party") },
+					stepName:  "deleting emoji",
+				},
+				{
+					expectedError: slack.ErrorEmojiDoesNotExist,
+					operation:     func() (err error) { return testCase.service.DeleteEmojiContext(ctx, "party") },
+					stepName:      "deleting missing emoji",
+				},
+			}
+
+			for _, step := range steps {
+				err := step.operation()
+				if (step.expectedError == nil && err != nil) ||
+					(step.expectedError != nil && !errors.Is(err, step.expectedError)) {
+					t.Fatalf("%s returned unexpected error, expected: '%+v', actual: '%+v'", step.stepName, step.expectedError, err)
+				}
+			}
+
+			emojis, err := testCase.service.GetEmojisContext(ctx)
+			if err != nil {
+				t.Fatalf("listing emojis failed, error: '%+v'", err)
+			} else if len(emojis) != 1 ||
+				emojis["yes"].AliasFor != "thumbsup" {
+				t.Errorf("remaining emojis mismatch, expected only the alias of the built-in emoji, actual: '%+v'", emojis)
+			}
+		})
+	}
+}
+
+func TestEmojiServiceRenameEmojiMovesAliases(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("party", newPNG(t, color.White))
+	server.AddAlias("party-alias", "party")
+	client := newTestAdminClient(t, server)
+
+	memoryService := slack.NewMemoryEmojiService(client.Emojis)
+
+	testCases := []struct {
+		caseDescription string
+		knownEmojis     func() (emojis map[string]slack.Emoji)
+		service         slack.EmojiService
+	}{
+		{
+			caseDescription: "client",
+			knownEmojis:     func() (emojis map[string]slack.Emoji) { return client.Emojis },
+			service:         client,
+		},
+		{
+			caseDescription: "memory service",
+			knownEmojis: func() (emojis map[string]slack.Emoji) {
+				emojis, _ = memoryService.GetEmojisContext(context.Background())
+
+				return emojis
+			},
+			service: memoryService,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			err := testCase.service.RenameEmojiContext(context.Background(), "party", "fiesta")
+			if err != nil {
+				t.Fatalf("renaming emoji failed, error: '%+v'", err)
+			}
+
+			emojis := testCase.knownEmojis()
+			if _, isExisting := emojis["party"]; isExisting {
+				t.Errorf("renamed emoji is still known under its old name, emojis: '%+v'", emojis)
+			} else if _, isExisting := emojis["fiesta"]; !isExisting {
+				t.Errorf("renamed emoji is not known under its new name, emojis: '%+v'", emojis)
+			} else if emojis["party-alias"].AliasFor != "fiesta" {
+				t.Errorf("alias of renamed emoji is not moved along, emojis: '%+v'", emojis)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MigrationConflict describes a source emoji which could not be migrated
+// under its own name.
+type MigrationConflict struct {
+	Name       string `json:"name"`
+	Reason     string `json:"reason"`
+	TargetName string `json:"target_name,omitempty"`
+}
+
+// String returns the human readable single line form of the conflict.
+func (conflict MigrationConflict) String() (text string) {
+	if conflict.TargetName != "" {
+		return fmt.Sprintf("%s -> %s: %s", conflict.Name, conflict.TargetName, conflict.Reason)
+	}
+
+	return fmt.Sprintf("%s: %s", conflict.Name, conflict.Reason)
+}
+
+// MigrationReport summarizes a workspace-to-workspace emoji migration.
+type MigrationReport struct {
+	AliasCount int                 `json:"alias_count"`
+	Conflicts  []MigrationConflict `json:"conflicts"`
+	EmojiCount int                 `json:"emoji_count"`
+}
+
+// String returns the human readable form of the report, one conflict per line
+// followed by a summary.
+func (report *MigrationReport) String() (text string) {
+	if report == nil {
+		return ""
+	}
+
+	builder := strings.Builder{}
+	for _, conflict := range report.Conflicts {
+		builder.WriteString(conflict.String() + "\n")
+	}
+
+	builder.WriteString(fmt.Sprintf("Migrated: %d emojis, %d aliases, conflicts: %d\n", report.EmojiCount, report.AliasCount, len(report.Conflicts)))
+
+	return builder.String()
+}
+
+// MigrateEmojis copies the custom emojis of the source team to the target
+// team without an intermediate directory, adding every image from its source
+// URL before recreating the aliases. Names existing on the target are
+// reported as conflicts and names taken by non-custom emojis fall back to the
+// taken prefixed and suffixed names.
+func MigrateEmojis(source, target *Client, emojiAliasTakenPrefix, emojiAliasTakenSuffix string) (report *MigrationReport, err error) {
+	return MigrateEmojisContext(context.Background(), source, target, emojiAliasTakenPrefix, emojiAliasTakenSuffix)
+}
+
+// MigrateEmojisContext is MigrateEmojis with a context cancelling its requests,
+// retries and rate limit waits.
+func MigrateEmojisContext(ctx context.Context, source, target *Client, emojiAliasTakenPrefix, emojiAliasTakenSuffix string) (report *MigrationReport, err error) {
+	if source == nil {
+		return nil, fmt.Errorf("source client is nil")
+	} else if target == nil {
+		return nil, fmt.Errorf("target client is nil")
+	} else if emojiAliasTakenSuffix == "" {
+		return nil, fmt.Errorf("invalid empty emoji alias taken suffix")
+	}
+
+	report = &MigrationReport{
+		Conflicts: make([]MigrationConflict, 0),
+	}
+	migratedNames := make(map[string]string)
+	aliases := make([]Emoji, 0)
+	for _, name := range source.emojiNames() {
+		emoji, _ := source.emoji(name)
+		if emoji.IsAliasEmoji() {
+			aliases = append(aliases, emoji)
+
+			continue
+		}
+
+		takenName := emojiAliasTakenPrefix + name + emojiAliasTakenSuffix
+		targetName, outcome, err := target.postWithTakenName(name, takenName, func(name string) (err error) {
+			return target.PostEmojiURLContext(ctx, name, emoji.URL)
+		})
+		if err != nil {
+			return report, errors.Wrapf(err, "uploading target emoji failed, name: '%+v'", name)
+		}
+
+		migratedNames[name] = targetName
+		report.addOutcome(name, targetName, outcome)
+		if outcome != UploadOutcomeSkipped {
+			report.EmojiCount++
+		}
+
+		log.Printf("%s: %s as %s\n", name, outcome, targetName)
+	}
+
+	sort.Slice(aliases, func(firstIndex, secondIndex int) (isLess bool) {
+		return aliases[firstIndex].Name < aliases[secondIndex].Name
+	})
+	for _, alias := range aliases {
+		aliasTargetName, isMigrated := migratedNames[alias.AliasFor]
+		if !isMigrated {
+			aliasTargetName = alias.AliasFor // Note: the aliases of built-in emojis are unknown to both clients.
+		}
+
+		takenName := emojiAliasTakenPrefix + alias.Name + emojiAliasTakenSuffix
+		targetName, outcome, err := target.postWithTakenName(alias.Name, takenName, func(name string) (err error) {
+			return target.PostAliasContext(ctx, name, aliasTargetName)
+		})
+		if errors.Is(err, ErrorInvalidAlias) {
+			report.Conflicts = append(report.Conflicts, MigrationConflict{
+				Name:   alias.Name,
+				Reason: "aliased emoji " + alias.AliasFor + " is missing on the target",
+			})
+
+			continue
+		} else if err != nil {
+			return report, errors.Wrapf(err, "adding target alias failed, name: '%+v', alias for: '%+v'", alias.Name, aliasTargetName)
+		}
+
+		report.addOutcome(alias.Name, targetName, outcome)
+		if outcome != UploadOutcomeSkipped {
+			report.AliasCount++
+		}
+
+		log.Printf("%s: alias for %s %s as %s\n", alias.Name, aliasTargetName, outcome, targetName)
+	}
+
+	return report, nil
+}
+
+// addOutcome records the conflict of a migrated emoji's outcome if there is
+// any.
+func (report *MigrationReport) addOutcome(name, targetName string, outcome UploadOutcome) {
+	switch outcome {
+	case UploadOutcomeNameTaken:
+		report.Conflicts = append(report.Conflicts, MigrationConflict{
+			Name:       name,
+			Reason:     "name is taken by non-custom emoji on the target",
+			TargetName: targetName,
+		})
+	case UploadOutcomeSkipped:
+		report.Conflicts = append(report.Conflicts, MigrationConflict{
+			Name:   name,
+			Reason: "emoji already exists on the target",
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package slack_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+	"github.com/pregnor/slack-emoji-upload/slack/slacktest"
+)
+
+func TestMigrateEmojisBuiltInAliasTarget(t *testing.T) {
+	sourceServer := slacktest.NewServer()
+	defer sourceServer.Close()
+
+	sourceServer.AddEmoji("party", newPNG(t, color.White))
+	sourceServer.AddAlias("missing-alias", "missing")
+	sourceServer.AddAlias("party-alias", "party")
+	sourceServer.AddAlias("yes", "thumbsup")
+	source := newTestClient(t, sourceServer)
+
+	targetServer := slacktest.NewServer()
+	defer targetServer.Close()
+
+	targetServer.ReserveNames("thumbsup")
+	target := newTestClient(t, targetServer)
+
+	report, err := slack.MigrateEmojis(source, target, "", "-2")
+	if err != nil {
+		t.Fatalf("migrating emojis failed, error: '%+v'", err)
+	}
+
+	emojis := targetServer.Emojis()
+	if emojis["party-alias"].AliasFor != "party" {
+		t.Errorf("alias of custom emoji is not migrated, emojis: '%+v'", emojis)
+	} else if emojis["yes"].AliasFor != "thumbsup" {
+		t.Errorf("alias of built-in emoji is not migrated, emojis: '%+v'", emojis)
+	}
+
+	if len(report.Conflicts) != 1 ||
+		report.Conflicts[0].Name != "missing-alias" {
+		t.Errorf("conflicts mismatch, expected the missing alias only, actual: '%+v'", report.Conflicts)
+	}
+}
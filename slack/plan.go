@@ -0,0 +1,180 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PlanAction describes the change planned for a single emoji.
+type PlanAction string
+
+const (
+	// PlanActionAdd marks an emoji to be uploaded.
+	PlanActionAdd PlanAction = "add"
+
+	// PlanActionDelete marks an emoji to be deleted.
+	PlanActionDelete PlanAction = "delete"
+
+	// PlanActionReplace marks an emoji to be deleted and added again.
+	PlanActionReplace PlanAction = "replace"
+
+	// PlanActionSkip marks an emoji file to be left alone.
+	PlanActionSkip PlanAction = "skip"
+)
+
+// PlanFormat describes the encoding a plan is written in.
+type PlanFormat string
+
+const (
+	// PlanFormatJSON writes the plan as an indented JSON document.
+	PlanFormatJSON PlanFormat = "json"
+
+	// PlanFormatText writes the plan as human readable lines.
+	PlanFormatText PlanFormat = "text"
+)
+
+// DryRun describes where and how a bulk operation writes its plan instead of
+// executing it.
+type DryRun struct {
+	Format PlanFormat
+	Writer io.Writer
+}
+
+// PlanItem describes the planned change of a single emoji.
+type PlanItem struct {
+	Action    PlanAction `json:"action"`
+	AliasFor  string     `json:"alias_for,omitempty"`
+	Name      string     `json:"name"`
+	Path      string     `json:"path,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+	TakenName string     `json:"taken_name,omitempty"`
+}
+
+// String returns the human readable single line form of the plan item.
+func (item PlanItem) String() (text string) {
+	builder := strings.Builder{}
+
+	switch item.Action {
+	case PlanActionAdd:
+		builder.WriteString("+ ")
+	case PlanActionDelete:
+		builder.WriteString("- ")
+	case PlanActionReplace:
+		builder.WriteString("~ ")
+	default:
+		builder.WriteString("  ")
+	}
+
+	builder.WriteString(item.Name)
+
+	if item.AliasFor != "" {
+		builder.WriteString(" -> " + item.AliasFor)
+	}
+
+	if item.Path != "" {
+		builder.WriteString(" <- " + item.Path)
+	}
+
+	if item.Action == PlanActionSkip {
+		builder.WriteString(" (skipped: " + item.Reason + ")")
+	} else if item.Reason != "" {
+		builder.WriteString(" (" + item.Reason + ")")
+	} else if item.TakenName != "" {
+		builder.WriteString(" (falls back to " + item.TakenName + " when taken by a non-custom emoji)")
+	}
+
+	return builder.String()
+}
+
+// Plan describes the changes a bulk operation would make.
+type Plan struct {
+	Items []PlanItem `json:"items"`
+}
+
+// Count returns the number of plan items with the specified action.
+func (plan *Plan) Count(action PlanAction) (count int) {
+	if plan == nil {
+		return 0
+	}
+
+	for _, item := range plan.Items {
+		if item.Action == action {
+			count++
+		}
+	}
+
+	return count
+}
+
+// HasChanges returns whether the plan contains any item to be executed.
+func (plan *Plan) HasChanges() (hasChanges bool) {
+	return plan.Count(PlanActionAdd)+plan.Count(PlanActionDelete)+plan.Count(PlanActionReplace) != 0
+}
+
+// String returns the human readable form of the plan, one item per line
+// followed by a summary.
+func (plan *Plan) String() (text string) {
+	if plan == nil {
+		return ""
+	}
+
+	builder := strings.Builder{}
+	for _, item := range plan.Items {
+		builder.WriteString(item.String() + "\n")
+	}
+
+	builder.WriteString(fmt.Sprintf(
+		"Plan: %d to add, %d to replace, %d to delete, %d to skip.\n",
+		plan.Count(PlanActionAdd),
+		plan.Count(PlanActionReplace),
+		plan.Count(PlanActionDelete),
+		plan.Count(PlanActionSkip),
+	))
+
+	return builder.String()
+}
+
+// Write writes the plan to the writer in the specified format.
+func (plan *Plan) Write(writer io.Writer, format PlanFormat) (err error) {
+	if plan == nil {
+		return fmt.Errorf("plan is nil")
+	} else if writer == nil {
+		return fmt.Errorf("writer is nil")
+	}
+
+	switch format {
+	case PlanFormatJSON:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(plan)
+		if err != nil {
+			return errors.Wrapf(err, "encoding JSON plan failed, plan: '%+v'", plan)
+		}
+	case PlanFormatText, "":
+		_, err = io.WriteString(writer, plan.String())
+		if err != nil {
+			return errors.Wrapf(err, "writing text plan failed, plan: '%+v'", plan)
+		}
+	default:
+		return fmt.Errorf("unsupported plan format, format: '%+v'", format)
+	}
+
+	return nil
+}
+
+// Write writes the plan according to the dry run, defaulting to the standard
+// output.
+func (dryRun *DryRun) Write(plan *Plan) (err error) {
+	writer := dryRun.Writer
+	if writer == nil {
+		writer = os.Stdout
+	}
+
+	return plan.Write(writer, dryRun.Format)
+}
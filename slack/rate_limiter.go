@@ -0,0 +1,110 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimitTier describes a Slack Web API rate limit tier.
+type RateLimitTier int
+
+const (
+	// RateLimitTier1 permits 1+ requests per minute.
+	RateLimitTier1 RateLimitTier = 1
+
+	// RateLimitTier2 permits 20+ requests per minute.
+	RateLimitTier2 RateLimitTier = 2
+
+	// RateLimitTier3 permits 50+ requests per minute.
+	RateLimitTier3 RateLimitTier = 3
+
+	// RateLimitTier4 permits 100+ requests per minute.
+	RateLimitTier4 RateLimitTier = 4
+
+	// DefaultAdminRateLimitTier is the tier the admin backend's client is
+	// limited to unless configured otherwise, the tier of the admin.emoji.*
+	// methods.
+	DefaultAdminRateLimitTier = RateLimitTier2
+
+	// DefaultRateLimitTier is the tier the session backend's client is
+	// limited to unless configured otherwise.
+	DefaultRateLimitTier = RateLimitTier4
+)
+
+// RequestsPerMinute returns the number of requests permitted per minute by
+// the tier or 0 for unknown tiers.
+func (tier RateLimitTier) RequestsPerMinute() (count int) {
+	switch tier {
+	case RateLimitTier1:
+		return 1
+	case RateLimitTier2:
+		return 20
+	case RateLimitTier3:
+		return 50
+	case RateLimitTier4:
+		return 100
+	default:
+		return 0
+	}
+}
+
+// RateLimiter spaces the requests of concurrent callers evenly according to a
+// Slack rate limit tier and holds all of them back while the server asks for
+// a pause through Retry-After.
+type RateLimiter struct {
+	interval time.Duration
+	mutex    sync.Mutex
+	nextSlot time.Time
+}
+
+// NewRateLimiter instantiates a rate limiter for the specified tier.
+func NewRateLimiter(tier RateLimitTier) (limiter *RateLimiter, err error) {
+	requestsPerMinute := tier.RequestsPerMinute()
+	if requestsPerMinute == 0 {
+		return nil, fmt.Errorf("unknown rate limit tier, tier: '%+v'", tier)
+	}
+
+	return &RateLimiter{
+		interval: time.Minute / time.Duration(requestsPerMinute),
+	}, nil
+}
+
+// Pause holds back every request until the specified duration elapses.
+func (limiter *RateLimiter) Pause(duration time.Duration) {
+	if limiter == nil {
+		return
+	}
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	resumeTime := time.Now().Add(duration)
+	if resumeTime.After(limiter.nextSlot) {
+		limiter.nextSlot = resumeTime
+	}
+}
+
+// Wait blocks until the caller is permitted to send its next request.
+func (limiter *RateLimiter) Wait() {
+	_ = limiter.WaitContext(context.Background())
+}
+
+// WaitContext blocks until the caller is permitted to send its next request
+// or the context is done, returning the context's error in the latter case.
+func (limiter *RateLimiter) WaitContext(ctx context.Context) (err error) {
+	if limiter == nil {
+		return ctx.Err()
+	}
+
+	limiter.mutex.Lock()
+	slot := limiter.nextSlot
+	if now := time.Now(); slot.Before(now) {
+		slot = now
+	}
+	limiter.nextSlot = slot.Add(limiter.interval)
+	limiter.mutex.Unlock()
+
+	return sleepContext(ctx, time.Until(slot))
+}
@@ -0,0 +1,52 @@
+package slack_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+	"github.com/pregnor/slack-emoji-upload/slack/slacktest"
+)
+
+func TestNewClientDefaultRateLimitTier(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	adminClient, err := slack.NewAdminClient(server.URL(), slacktest.DefaultAPIToken)
+	if err != nil {
+		t.Fatalf("instantiating admin client failed, error: '%+v'", err)
+	}
+
+	sessionClient, err := slack.NewSlackClientWithOptions("slacktest", "d=slacktest", slack.ClientOptions{BaseURL: server.URL()})
+	if err != nil {
+		t.Fatalf("instantiating session client failed, error: '%+v'", err)
+	}
+
+	testCases := []struct {
+		caseDescription  string
+		client           *slack.Client
+		expectedInterval time.Duration
+	}{
+		{
+			caseDescription:  "admin backend",
+			client:           adminClient,
+			expectedInterval: time.Minute / time.Duration(slack.RateLimitTier2.RequestsPerMinute()),
+		},
+		{
+			caseDescription:  "session backend",
+			client:           sessionClient,
+			expectedInterval: time.Minute / time.Duration(slack.RateLimitTier4.RequestsPerMinute()),
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			actualInterval := slack.RateLimiterInterval(testCase.client.RateLimiter)
+			if actualInterval != testCase.expectedInterval {
+				t.Errorf("rate limit interval mismatches, expected: '%+v', actual: '%+v'", testCase.expectedInterval, actualInterval)
+			}
+		})
+	}
+}
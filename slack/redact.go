@@ -0,0 +1,36 @@
+package slack
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// RedactedText replaces the secrets in redacted texts.
+	RedactedText = "[REDACTED]"
+)
+
+var (
+	secretHeaderRegex    = regexp.MustCompile(`(?im)^((?:authorization|cookie|set-cookie):[ \t]*)[^\r\n]*`)
+	secretJSONFieldRegex = regexp.MustCompile(`(?i)("?(?:api_token|cookie|token)"?\s*:\s*")[^"]*`)
+	secretParameterRegex = regexp.MustCompile(`(?i)((?:^|[?&\s'"])(?:token|d)=)[^&\s'"]+`)
+	slackTokenRegex      = regexp.MustCompile(`xox[a-z]-[0-9A-Za-z%./+=_\-]+`)
+)
+
+// Redact returns the text with the specified secrets, the cookie and
+// authorization headers, the token fields and parameters and every Slack
+// token replaced by RedactedText.
+func Redact(text string, secrets ...string) (redactedText string) {
+	for _, secret := range secrets {
+		if secret != "" {
+			text = strings.Replace(text, secret, RedactedText, -1)
+		}
+	}
+
+	text = secretHeaderRegex.ReplaceAllString(text, "${1}"+RedactedText)
+	text = secretJSONFieldRegex.ReplaceAllString(text, "${1}"+RedactedText)
+	text = secretParameterRegex.ReplaceAllString(text, "${1}"+RedactedText)
+	text = slackTokenRegex.ReplaceAllString(text, RedactedText)
+
+	return text
+}
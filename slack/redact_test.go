@@ -0,0 +1,84 @@
+package slack_test
+
+import (
+	"testing"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+)
+
+func TestRedact(t *testing.T) {
+	testCases := []struct {
+		caseDescription string
+		expectedText    string
+		secrets         []string
+		text            string
+	}{
+		{
+			caseDescription: "authorization header",
+			expectedText:    "POST /api/emoji.add HTTP/1.1\r\nAuthorization: " + slack.RedactedText + "\r\nHost: slack.com",
+			text:            "POST /api/emoji.add HTTP/1.1\r\nAuthorization: Bearer secret-bearer\r\nHost: slack.com",
+		},
+		{
+			caseDescription: "cookie header",
+			expectedText:    "GET /customize/emoji HTTP/1.1\nCookie: " + slack.RedactedText + "\nAccept: */*",
+			text:            "GET /customize/emoji HTTP/1.1\nCookie: d=secret-cookie; b=other\nAccept: */*",
+		},
+		{
+			caseDescription: "set-cookie header",
+			expectedText:    "HTTP/1.1 200 OK\r\nSet-Cookie: " + slack.RedactedText,
+			text:            "HTTP/1.1 200 OK\r\nSet-Cookie: d=secret-cookie; Path=/",
+		},
+		{
+			caseDescription: "api_token JSON field",
+			expectedText:    `{"api_token":"` + slack.RedactedText + `","ok":true}`,
+			text:            `{"api_token":"secret-token","ok":true}`,
+		},
+		{
+			caseDescription: "token JSON field",
+			expectedText:    `{"ok": true, "token": "` + slack.RedactedText + `"}`,
+			text:            `{"ok": true, "token": "secret-token"}`,
+		},
+		{
+			caseDescription: "token form parameter",
+			expectedText:    "name=party&token=" + slack.RedactedText + "&mode=data",
+			text:            "name=party&token=secret-token&mode=data",
+		},
+		{
+			caseDescription: "token query parameter",
+			expectedText:    "https://slack.com/api/emoji.adminList?token=" + slack.RedactedText,
+			text:            "https://slack.com/api/emoji.adminList?token=secret-token",
+		},
+		{
+			caseDescription: "cookie parameter",
+			expectedText:    "signing in failed, cookie 'd=" + slack.RedactedText + "'",
+			text:            "signing in failed, cookie 'd=secret-cookie'",
+		},
+		{
+			caseDescription: "bare Slack tokens",
+			expectedText:    "tokens " + slack.RedactedText + ", " + slack.RedactedText + " and " + slack.RedactedText,
+			text:            "tokens xoxs-1234-abcd, xoxp-5678.efgh and xoxd-a%2Fb%3D",
+		},
+		{
+			caseDescription: "specified secrets",
+			expectedText:    "team " + slack.RedactedText + " failed",
+			secrets:         []string{"", "secret-team"},
+			text:            "team secret-team failed",
+		},
+		{
+			caseDescription: "no secrets",
+			expectedText:    "emoji party is uploaded, token count: 2",
+			text:            "emoji party is uploaded, token count: 2",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			actualText := slack.Redact(testCase.text, testCase.secrets...)
+			if actualText != testCase.expectedText {
+				t.Errorf("redacted text mismatches, expected: '%+v', actual: '%+v'", testCase.expectedText, actualText)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"time"
+
+	backoff "github.com/cenkalti/backoff/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/resty.v1"
+)
+
+// RequestAttempt describes a single attempt of a request sent through the
+// client's request pipeline. The duration, the error and the status code are
+// only set after the attempt completed.
+type RequestAttempt struct {
+	Duration   time.Duration
+	Error      error
+	Method     string
+	Number     int
+	StatusCode int
+	URL        string
+}
+
+// RequestHooks describes the callbacks invoked around every request attempt
+// of a client. The hooks are called concurrently by concurrent uploads.
+type RequestHooks struct {
+	// AfterResponse is called after every attempt with its outcome.
+	AfterResponse func(attempt RequestAttempt)
+
+	// BeforeRequest is called before every attempt is sent, after waiting
+	// for the rate limit.
+	BeforeRequest func(attempt RequestAttempt)
+}
+
+// slackEnvelope describes the common fields of every Slack API response.
+type slackEnvelope struct {
+	Error string `json:"error"`
+	IsOk  *bool  `json:"ok"`
+}
+
+// slackRequest describes a request sent through the client's request
+// pipeline.
+type slackRequest struct {
+	// description names the request in the retry logs.
+	description string
+
+	// isRateLimited waits for the client's rate limiter before every attempt.
+	isRateLimited bool
+
+	// method is the HTTP method of the request.
+	method string
+
+	// newRequest builds the request anew for every attempt.
+	newRequest func() (request *resty.Request)
+
+	// result is the value the Slack response envelope is decoded into, the
+	// envelope is not decoded when it is nil.
+	result interface{}
+
+	// uri is the URI the request is sent to.
+	uri string
+}
+
+// afterResponse calls the after response hook if there is one.
+func (hooks *RequestHooks) afterResponse(attempt RequestAttempt) {
+	if hooks == nil ||
+		hooks.AfterResponse == nil {
+		return
+	}
+
+	hooks.AfterResponse(attempt)
+}
+
+// beforeRequest calls the before request hook if there is one.
+func (hooks *RequestHooks) beforeRequest(attempt RequestAttempt) {
+	if hooks == nil ||
+		hooks.BeforeRequest == nil {
+		return
+	}
+
+	hooks.BeforeRequest(attempt)
+}
+
+// sendRequest sends the request retrying its transient failures, waiting out
+// the Retry-After delay of rate limited responses on every endpoint,
+// refreshing a rejected API token once, failing on permanent HTTP and Slack
+// errors and decoding the Slack response envelope into the request's result
+// when it is set.
+func (client *Client) sendRequest(ctx context.Context, slackRequest slackRequest) (response *resty.Response, err error) {
+	attemptNumber := 0
+	innerError := (error)(nil)
+	isAPITokenRefreshed := false
+
+	err = backoff.RetryNotifyWithTimer(
+		func() (err error) {
+			if slackRequest.isRateLimited {
+				err = client.RateLimiter.WaitContext(ctx)
+				if err != nil {
+					innerError = errors.Wrap(err, "waiting for rate limit failed")
+
+					return backoff.Permanent(innerError)
+				}
+			}
+
+			attemptNumber++
+			attempt := RequestAttempt{
+				Method: slackRequest.method,
+				Number: attemptNumber,
+				URL:    slackRequest.uri,
+			}
+			client.RequestHooks.beforeRequest(attempt)
+
+			apiToken := client.currentAPIToken()
+			startTime := time.Now()
+			response, innerError = client.sendRequestAttempt(slackRequest)
+
+			attempt.Duration = time.Since(startTime)
+			attempt.Error = innerError
+			if response != nil {
+				attempt.StatusCode = response.StatusCode()
+			}
+			client.RequestHooks.afterResponse(attempt)
+
+			statusError := (*StatusError)(nil)
+			if errors.As(innerError, &statusError) &&
+				statusError.RetryAfter > 0 {
+				log.Printf("waiting rate limit for %s\n", statusError.RetryAfter)
+				client.RateLimiter.Pause(statusError.RetryAfter)
+				err = sleepContext(ctx, statusError.RetryAfter)
+				if err != nil {
+					innerError = errors.Wrap(err, "waiting for rate limit failed")
+
+					return backoff.Permanent(innerError)
+				}
+			}
+
+			if isAuthError(innerError) &&
+				!isAPITokenRefreshed &&
+				client.canRefreshAPIToken() {
+				isAPITokenRefreshed = true
+				err = client.refreshAPIToken(ctx, apiToken)
+				if err != nil {
+					innerError = errors.WithMessagef(err, "refreshing rejected API token failed, error: '%v'", innerError)
+
+					return backoff.Permanent(innerError)
+				}
+
+				return innerError
+			}
+
+			if IsPermanentError(innerError) {
+				return backoff.Permanent(innerError)
+			}
+
+			return innerError
+		},
+		backoff.WithContext(client.newBackoffStrategy(), ctx),
+		func(err error, backoffDelay time.Duration) {
+			log.Printf("requesting %s temporarily failed and will be retried, error: '%+v', backoff delay: '%+v'\n", slackRequest.description, err, backoffDelay)
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, retryError(ctx, innerError)
+	}
+
+	return response, nil
+}
+
+// sendRequestAttempt sends a single attempt of the request, classifying its
+// HTTP error status and decoding its Slack response envelope.
+func (client *Client) sendRequestAttempt(slackRequest slackRequest) (response *resty.Response, err error) {
+	request := slackRequest.newRequest()
+	response, err = request.Execute(slackRequest.method, slackRequest.uri)
+	if err != nil {
+		requestDump := []byte(nil)
+		if request.RawRequest != nil {
+			requestDump, _ = httputil.DumpRequest(request.RawRequest, false)
+		}
+
+		return nil, errors.Wrapf(err, "request failed, request dump: '%+v'", client.redact(string(requestDump)))
+	}
+
+	_ = response.RawResponse.Body.Close()
+
+	if response.StatusCode() >= 400 &&
+		response.StatusCode() < 600 {
+		statusError := NewStatusError(response.StatusCode(), retryAfter(response.Header().Get("Retry-After")))
+
+		return response, errors.WithMessagef(statusError, "response contains error status, response dump: '%+v'", client.redact(responseDump(response)))
+	} else if slackRequest.result == nil {
+		return response, nil
+	}
+
+	envelope := slackEnvelope{}
+	err = json.Unmarshal(response.Body(), &envelope)
+	if err != nil {
+		return response, errors.Wrapf(err, "unmarshalling JSON response failed, raw JSON response: '%+v'", client.redact(string(response.Body())))
+	} else if envelope.IsOk == nil {
+		return response, fmt.Errorf("response OK flag is missing, raw JSON response: '%+v'", client.redact(string(response.Body())))
+	} else if !*envelope.IsOk {
+		return response, newSlackResponseError(envelope.Error, client.redact(string(response.Body())))
+	}
+
+	err = json.Unmarshal(response.Body(), slackRequest.result)
+	if err != nil {
+		return response, errors.Wrapf(err, "unmarshalling JSON response failed, raw JSON response: '%+v'", client.redact(string(response.Body())))
+	}
+
+	return response, nil
+}
+
+// responseDump returns the dump of the response's status line, headers and
+// already read body.
+func responseDump(response *resty.Response) (dump string) {
+	headerDump, _ := httputil.DumpResponse(response.RawResponse, false)
+
+	return string(headerDump) + string(response.Body())
+}
+
+// retryAfter returns the delay requested by a Retry-After header value in
+// either of its delay seconds or HTTP date forms, zero when it is missing or
+// malformed.
+func retryAfter(value string) (delay time.Duration) {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if retryTime, err := http.ParseTime(value); err == nil {
+		return time.Until(retryTime)
+	}
+
+	return 0
+}
@@ -0,0 +1,96 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// RestoreEmojis recreates the emojis described by the manifest of the
+// specified backup directory, uploading the image emojis first and adding
+// their aliases afterwards, falling back to the taken prefixed and suffixed
+// names for names taken by non-custom emojis and skipping the aliases of
+// missing targets.
+func (client *Client) RestoreEmojis(directoryPath, emojiAliasTakenPrefix, emojiAliasTakenSuffix string) (err error) {
+	return client.RestoreEmojisContext(context.Background(), directoryPath, emojiAliasTakenPrefix, emojiAliasTakenSuffix)
+}
+
+// RestoreEmojisContext is RestoreEmojis with a context cancelling its requests,
+// retries and rate limit waits.
+func (client *Client) RestoreEmojisContext(ctx context.Context, directoryPath, emojiAliasTakenPrefix, emojiAliasTakenSuffix string) (err error) {
+	if client == nil {
+		return fmt.Errorf("client is nil")
+	} else if directoryPath == "" {
+		return fmt.Errorf("invalid empty directory path")
+	} else if emojiAliasTakenSuffix == "" {
+		return fmt.Errorf("invalid empty emoji alias taken suffix")
+	}
+
+	manifest, err := NewManifestFromFile(filepath.Join(directoryPath, ManifestFileName))
+	if err != nil {
+		return errors.Wrapf(err, "loading manifest failed, directory path: '%+v'", directoryPath)
+	}
+
+	restoredNames := make(map[string]string, len(manifest.Emojis))
+	aliasTargets := make(map[string]string)
+	for _, manifestEmoji := range manifest.Emojis {
+		if manifestEmoji.IsAlias() {
+			aliasTargets[manifestEmoji.Name] = manifestEmoji.AliasFor
+
+			continue
+		}
+
+		for _, aliasName := range manifestEmoji.Aliases {
+			aliasTargets[aliasName] = manifestEmoji.Name
+		}
+
+		if manifestEmoji.FileName == "" {
+			return fmt.Errorf("image emoji misses file name, name: '%+v'", manifestEmoji.Name)
+		}
+
+		path := filepath.Join(directoryPath, manifestEmoji.FileName)
+		takenName := emojiAliasTakenPrefix + manifestEmoji.Name + emojiAliasTakenSuffix
+		name, outcome, err := client.postWithTakenName(manifestEmoji.Name, takenName, func(name string) (err error) {
+			return client.PostEmojiContext(ctx, name, path)
+		})
+		if err != nil {
+			return errors.Wrapf(err, "restoring emoji failed, name: '%+v', path: '%+v'", manifestEmoji.Name, path)
+		}
+
+		log.Printf("%s: %s as %s\n", manifestEmoji.Name, outcome, name)
+		restoredNames[manifestEmoji.Name] = name
+	}
+
+	aliasNames := make([]string, 0, len(aliasTargets))
+	for aliasName := range aliasTargets {
+		aliasNames = append(aliasNames, aliasName)
+	}
+	sort.Strings(aliasNames)
+
+	for _, aliasName := range aliasNames {
+		targetName := aliasTargets[aliasName]
+		if restoredName, isRestored := restoredNames[targetName]; isRestored {
+			targetName = restoredName
+		}
+
+		takenName := emojiAliasTakenPrefix + aliasName + emojiAliasTakenSuffix
+		name, outcome, err := client.postWithTakenName(aliasName, takenName, func(name string) (err error) {
+			return client.PostAliasContext(ctx, name, targetName)
+		})
+		if errors.Is(err, ErrorInvalidAlias) {
+			log.Printf("%s: skipped alias for missing %s\n", aliasName, targetName)
+
+			continue
+		} else if err != nil {
+			return errors.Wrapf(err, "restoring alias failed, name: '%+v', target name: '%+v'", aliasName, targetName)
+		}
+
+		log.Printf("%s: alias for %s %s as %s\n", aliasName, targetName, outcome, name)
+	}
+
+	return nil
+}
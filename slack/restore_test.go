@@ -0,0 +1,58 @@
+package slack_test
+
+import (
+	"encoding/json"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+	"github.com/pregnor/slack-emoji-upload/slack/slacktest"
+)
+
+func TestRestoreEmojisBuiltInAliasTarget(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.ReserveNames("thumbsup")
+	client := newTestClient(t, server)
+
+	directoryPath, removeDirectory := newTempDirectory(t)
+	defer removeDirectory()
+	writeFile(t, filepath.Join(directoryPath, "party.png"), newPNG(t, color.White))
+	writeManifest(t, directoryPath, slack.Manifest{
+		Emojis: []slack.ManifestEmoji{
+			{Aliases: []string{"party-alias"}, FileName: "party.png", Name: "party"},
+			{AliasFor: "missing", Name: "missing-alias"},
+			{AliasFor: "thumbsup", Name: "yes"},
+		},
+	})
+
+	err := client.RestoreEmojis(directoryPath, "", "-2")
+	if err != nil {
+		t.Fatalf("restoring emojis failed, error: '%+v'", err)
+	}
+
+	emojis := server.Emojis()
+	if _, isExisting := emojis["party"]; !isExisting {
+		t.Errorf("image emoji is not restored, emojis: '%+v'", emojis)
+	} else if emojis["party-alias"].AliasFor != "party" {
+		t.Errorf("alias is not restored, emojis: '%+v'", emojis)
+	} else if emojis["yes"].AliasFor != "thumbsup" {
+		t.Errorf("alias of built-in emoji is not restored, emojis: '%+v'", emojis)
+	} else if _, isExisting := emojis["missing-alias"]; isExisting {
+		t.Errorf("alias of missing emoji is restored")
+	}
+}
+
+// writeManifest writes the manifest into the backup directory.
+func writeManifest(t *testing.T, directoryPath string, manifest slack.Manifest) {
+	t.Helper()
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("marshalling manifest failed, error: '%+v'", err)
+	}
+
+	writeFile(t, filepath.Join(directoryPath, slack.ManifestFileName), data)
+}
@@ -0,0 +1,147 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"gopkg.in/resty.v1"
+)
+
+// sessionBackend manages the custom emojis through the undocumented emoji.*
+// endpoints of the web client, authorized by the API token scraped with the
+// browser session cookie.
+type sessionBackend struct {
+	client *Client
+}
+
+// addAlias sends the emoji.add request of an alias.
+func (backend *sessionBackend) addAlias(ctx context.Context, aliasName, targetName string) (err error) {
+	return backend.postEmojiAdd(ctx, aliasName, func() (request *resty.Request) {
+		return backend.client.restClient.R().SetContext(ctx).
+			SetFormData(
+				map[string]string{
+					"alias_for": targetName,
+					"mode":      "alias",
+					"name":      aliasName,
+					"token":     backend.client.currentAPIToken(),
+				},
+			)
+	})
+}
+
+// addEmojiData sends the emoji.add request of the image data as a file of the
+// specified name.
+func (backend *sessionBackend) addEmojiData(ctx context.Context, emojiName, fileName string, data []byte) (err error) {
+	return backend.postEmojiAdd(ctx, emojiName, func() (request *resty.Request) {
+		return backend.client.restClient.R().SetContext(ctx).
+			SetFormData(
+				map[string]string{
+					"mode":  "data",
+					"name":  emojiName,
+					"token": backend.client.currentAPIToken(),
+				},
+			).
+			SetFileReader("image", fileName, bytes.NewReader(data))
+	})
+}
+
+// addEmojiURL downloads the image of the URL into memory and sends it in an
+// emoji.add request, as the endpoint only accepts image data. The download
+// fails before reading the whole image when it exceeds the file size limit of
+// Slack.
+func (backend *sessionBackend) addEmojiURL(ctx context.Context, emojiName, url string) (err error) {
+	data, extension, err := backend.client.DownloadEmojiContext(withMaxDownloadSize(ctx, DefaultImageConstraints.MaxFileSize), Emoji{
+		Name: emojiName,
+		URL:  url,
+	})
+	if err != nil {
+		return err
+	}
+
+	return backend.addEmojiData(ctx, emojiName, emojiName+extension, data)
+}
+
+// listEmojis sends the emoji.adminList requests of every page.
+func (backend *sessionBackend) listEmojis(ctx context.Context) (emojis map[string]Emoji, err error) {
+	emojis = make(map[string]Emoji)
+	page := 1
+	pageCount := 2
+	pageSize := 1000
+
+	for page <= pageCount {
+		responseJSON := EmojiListResponse{}
+		_, err = backend.client.sendRequest(ctx, slackRequest{
+			description:   "emoji list",
+			isRateLimited: true,
+			method:        http.MethodPost,
+			newRequest: func() (request *resty.Request) {
+				return backend.client.restClient.R().SetContext(ctx).
+					SetFormData(
+						map[string]string{
+							"count": fmt.Sprintf("%d", pageSize),
+							"page":  fmt.Sprintf("%d", page),
+							"query": "",
+							"token": backend.client.currentAPIToken(),
+						},
+					)
+			},
+			result: &responseJSON,
+			uri:    backend.client.EmojiAdminListURI(),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, emoji := range responseJSON.Emojis {
+			emojis[emoji.Name] = emoji
+		}
+
+		pageCount = responseJSON.Paging.PageCount
+		page = responseJSON.Paging.Page + 1
+	}
+
+	return emojis, nil
+}
+
+// removeEmoji sends the emoji.remove request.
+func (backend *sessionBackend) removeEmoji(ctx context.Context, emojiName string) (err error) {
+	_, err = backend.client.sendRequest(ctx, slackRequest{
+		description:   fmt.Sprintf("emoji removal of '%s'", emojiName),
+		isRateLimited: true,
+		method:        http.MethodPost,
+		newRequest: func() (request *resty.Request) {
+			return backend.client.restClient.R().SetContext(ctx).
+				SetFormData(
+					map[string]string{
+						"name":  emojiName,
+						"token": backend.client.currentAPIToken(),
+					},
+				)
+		},
+		result: &slackEnvelope{},
+		uri:    backend.client.EmojiRemoveURI(),
+	})
+
+	return err
+}
+
+// renameEmoji fails as the web client has no endpoint renaming emojis.
+func (backend *sessionBackend) renameEmoji(ctx context.Context, emojiName, newName string) (err error) {
+	return ErrorUnsupportedOperation
+}
+
+// postEmojiAdd sends the emoji.add request built anew for every attempt.
+func (backend *sessionBackend) postEmojiAdd(ctx context.Context, emojiName string, newRequest func() (request *resty.Request)) (err error) {
+	_, err = backend.client.sendRequest(ctx, slackRequest{
+		description:   fmt.Sprintf("emoji addition of '%s'", emojiName),
+		isRateLimited: true,
+		method:        http.MethodPost,
+		newRequest:    newRequest,
+		result:        &slackEnvelope{},
+		uri:           backend.client.EmojiAddURI(),
+	})
+
+	return err
+}
@@ -0,0 +1,220 @@
+package slacktest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// AdminEmojiAddAliasPath is the path of the served api/admin.emoji.addAlias
+	// method.
+	AdminEmojiAddAliasPath = "/api/admin.emoji.addAlias"
+
+	// AdminEmojiAddPath is the path of the served api/admin.emoji.add method.
+	AdminEmojiAddPath = "/api/admin.emoji.add"
+
+	// AdminEmojiListPath is the path of the served api/admin.emoji.list
+	// method.
+	AdminEmojiListPath = "/api/admin.emoji.list"
+
+	// AdminEmojiRemovePath is the path of the served api/admin.emoji.remove
+	// method.
+	AdminEmojiRemovePath = "/api/admin.emoji.remove"
+
+	// AdminEmojiRenamePath is the path of the served api/admin.emoji.rename
+	// method.
+	AdminEmojiRenamePath = "/api/admin.emoji.rename"
+)
+
+// handleAdminEmojiAdd serves the api/admin.emoji.add method, fetching the
+// image from the URL of the request.
+func (server *Server) handleAdminEmojiAdd(writer http.ResponseWriter, request *http.Request) {
+	if server.serveFault(writer, request) ||
+		!server.authorizeBearer(writer, request) {
+		return
+	}
+
+	name := request.FormValue("name")
+	if !emojiNameRegex.MatchString(name) {
+		writeSlackError(writer, "invalid_name")
+
+		return
+	}
+
+	response, err := http.Get(request.FormValue("url"))
+	if err != nil {
+		writeSlackError(writer, "error_bad_upload")
+
+		return
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	image, err := ioutil.ReadAll(response.Body)
+	if err != nil ||
+		response.StatusCode != http.StatusOK {
+		writeSlackError(writer, "error_bad_upload")
+
+		return
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if _, isExisting := server.emojis[name]; isExisting ||
+		server.reservedNames[name] {
+		writeSlackError(writer, "error_name_taken")
+
+		return
+	}
+
+	server.addEmoji(name, image)
+
+	writeJSON(writer, map[string]interface{}{"ok": true})
+}
+
+// handleAdminEmojiAddAlias serves the api/admin.emoji.addAlias method.
+func (server *Server) handleAdminEmojiAddAlias(writer http.ResponseWriter, request *http.Request) {
+	if server.serveFault(writer, request) ||
+		!server.authorizeBearer(writer, request) {
+		return
+	}
+
+	server.handleEmojiAddAlias(writer, request.FormValue("name"), request.FormValue("alias_for"))
+}
+
+// handleAdminEmojiList serves the cursor paged api/admin.emoji.list method.
+func (server *Server) handleAdminEmojiList(writer http.ResponseWriter, request *http.Request) {
+	if server.serveFault(writer, request) ||
+		!server.authorizeBearer(writer, request) {
+		return
+	}
+
+	offset, err := strconv.Atoi(request.FormValue("cursor"))
+	if err != nil ||
+		offset < 0 {
+		offset = 0
+	}
+
+	limit, err := strconv.Atoi(request.FormValue("limit"))
+	if err != nil ||
+		limit < 1 {
+		limit = 100
+	}
+
+	server.mutex.Lock()
+	names := make([]string, 0, len(server.emojis))
+	for name := range server.emojis {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	emojis := make(map[string]interface{})
+	for index := offset; index < len(names) && index < offset+limit; index++ {
+		emoji := server.emojis[names[index]]
+		emojis[emoji.Name] = map[string]interface{}{
+			"date_created": emoji.Created,
+			"uploaded_by":  emoji.UserID,
+			"url":          emoji.URL,
+		}
+	}
+	server.mutex.Unlock()
+
+	nextCursor := ""
+	if offset+limit < len(names) {
+		nextCursor = strconv.Itoa(offset + limit)
+	}
+
+	writeJSON(writer, map[string]interface{}{
+		"emoji": emojis,
+		"ok":    true,
+		"response_metadata": map[string]interface{}{
+			"next_cursor": nextCursor,
+		},
+	})
+}
+
+// handleAdminEmojiRemove serves the api/admin.emoji.remove method.
+func (server *Server) handleAdminEmojiRemove(writer http.ResponseWriter, request *http.Request) {
+	if server.serveFault(writer, request) ||
+		!server.authorizeBearer(writer, request) {
+		return
+	}
+
+	server.removeEmoji(writer, request.FormValue("name"))
+}
+
+// handleAdminEmojiRename serves the api/admin.emoji.rename method, moving the
+// aliases of the renamed emoji along.
+func (server *Server) handleAdminEmojiRename(writer http.ResponseWriter, request *http.Request) {
+	if server.serveFault(writer, request) ||
+		!server.authorizeBearer(writer, request) {
+		return
+	}
+
+	name := request.FormValue("name")
+	newName := request.FormValue("new_name")
+	if !emojiNameRegex.MatchString(newName) {
+		writeSlackError(writer, "invalid_name")
+
+		return
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	emoji, isExisting := server.emojis[name]
+	if !isExisting {
+		writeSlackError(writer, "emoji_not_found")
+
+		return
+	} else if _, isExisting = server.emojis[newName]; isExisting ||
+		server.reservedNames[newName] {
+		writeSlackError(writer, "error_name_taken")
+
+		return
+	}
+
+	delete(server.emojis, name)
+	if emoji.IsAlias != 0 {
+		server.addAlias(newName, emoji.AliasFor)
+	} else {
+		server.addEmoji(newName, server.images[name])
+		delete(server.images, name)
+
+		for aliasName, alias := range server.emojis {
+			if alias.AliasFor == name {
+				server.addAlias(aliasName, newName)
+			}
+		}
+	}
+
+	writeJSON(writer, map[string]interface{}{"ok": true})
+}
+
+// authorizeBearer parses the request form and checks the bearer token of its
+// authorization header, writing a not_authed or invalid_auth response and
+// returning false on mismatch.
+func (server *Server) authorizeBearer(writer http.ResponseWriter, request *http.Request) (isAuthorized bool) {
+	err := request.ParseForm()
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return false
+	}
+
+	authorization := request.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		writeSlackError(writer, "not_authed")
+
+		return false
+	} else if strings.TrimPrefix(authorization, "Bearer ") != server.currentAPIToken() {
+		writeSlackError(writer, "invalid_auth")
+
+		return false
+	}
+
+	return true
+}
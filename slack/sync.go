@@ -0,0 +1,261 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SyncOptions describes the optional behaviour of desired-state
+// synchronization.
+type SyncOptions struct {
+	// ImageConstraints validates every emoji file when set, skipping the
+	// invalid files with their reason instead of adding or replacing them.
+	ImageConstraints *ImageConstraints
+
+	// PruneOwnedPrefix deletes the remote emojis with the specified name
+	// prefix which are not desired by the directory, an empty prefix never
+	// deletes anything.
+	PruneOwnedPrefix string
+}
+
+// ApplyPlan executes the changes of the plan in order, falling back to the
+// taken prefixed and suffixed names for added names taken by non-custom
+// emojis and adding the aliases of replaced emojis again.
+func (client *Client) ApplyPlan(plan *Plan, emojiAliasTakenPrefix, emojiAliasTakenSuffix string) (err error) {
+	return client.ApplyPlanContext(context.Background(), plan, emojiAliasTakenPrefix, emojiAliasTakenSuffix)
+}
+
+// ApplyPlanContext is ApplyPlan with a context cancelling its requests, retries
+// and rate limit waits.
+func (client *Client) ApplyPlanContext(ctx context.Context, plan *Plan, emojiAliasTakenPrefix, emojiAliasTakenSuffix string) (err error) {
+	if client == nil {
+		return fmt.Errorf("client is nil")
+	} else if plan == nil {
+		return fmt.Errorf("plan is nil")
+	} else if emojiAliasTakenSuffix == "" {
+		return fmt.Errorf("invalid empty emoji alias taken suffix")
+	}
+
+	for _, item := range plan.Items {
+		post := func(name string) (err error) {
+			if item.AliasFor != "" {
+				return client.PostAliasContext(ctx, name, item.AliasFor)
+			}
+
+			return client.PostEmojiContext(ctx, name, item.Path)
+		}
+
+		switch item.Action {
+		case PlanActionAdd:
+			takenName := item.TakenName
+			if takenName == "" {
+				takenName = emojiAliasTakenPrefix + item.Name + emojiAliasTakenSuffix
+			}
+
+			name, outcome, err := client.postWithTakenName(item.Name, takenName, post)
+			if err != nil {
+				return errors.Wrapf(err, "adding emoji failed, item: '%+v'", item)
+			}
+
+			log.Printf("%s: %s as %s\n", item.Name, outcome, name)
+		case PlanActionDelete:
+			err = client.DeleteEmojiContext(ctx, item.Name)
+			if err != nil {
+				return errors.Wrapf(err, "deleting emoji failed, item: '%+v'", item)
+			}
+
+			log.Printf("%s: deleted\n", item.Name)
+		case PlanActionReplace:
+			err = client.replaceEmoji(ctx, item.Name, func() (err error) { return post(item.Name) })
+			if err != nil {
+				return errors.Wrapf(err, "replacing emoji failed, item: '%+v'", item)
+			}
+
+			log.Printf("%s: replaced\n", item.Name)
+		case PlanActionSkip:
+		default:
+			return fmt.Errorf("unknown plan action, item: '%+v'", item)
+		}
+	}
+
+	return nil
+}
+
+// PlanSync returns the plan of making the connected Slack team match the
+// emoji directory: the files mapped to their prefixed and suffixed names and
+// the aliases of the directory's aliases sidecar file. Missing emojis are
+// added, emojis with a differing image or alias target are replaced and, when
+// pruning, the owned emojis not desired by the directory are deleted.
+func (client *Client) PlanSync(emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix string, options SyncOptions) (plan *Plan, err error) {
+	return client.PlanSyncContext(context.Background(), emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix, options)
+}
+
+// PlanSyncContext is PlanSync with a context cancelling its requests, retries
+// and rate limit waits.
+func (client *Client) PlanSyncContext(ctx context.Context, emojiDirectoryPath, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix string, options SyncOptions) (plan *Plan, err error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is nil")
+	} else if emojiDirectoryPath == "" {
+		return nil, fmt.Errorf("invalid empty emoji directory path")
+	}
+
+	paths, err := emojiFilePaths(emojiDirectoryPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "iterating emoji directory failed, emoji directory path: '%+v'", emojiDirectoryPath)
+	}
+
+	aliases, err := directoryAliases(emojiDirectoryPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading directory aliases failed, emoji directory path: '%+v'", emojiDirectoryPath)
+	}
+
+	plan = &Plan{
+		Items: make([]PlanItem, 0, len(paths)+len(aliases)),
+	}
+	desiredNames := make(map[string]string, len(paths)+len(aliases))
+	for _, path := range paths {
+		item, err := client.planSyncFile(ctx, path, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix, options.ImageConstraints, desiredNames)
+		if err != nil {
+			return nil, errors.Wrapf(err, "planning emoji file failed, path: '%+v'", path)
+		}
+
+		plan.Items = append(plan.Items, item)
+	}
+
+	for _, aliasName := range sortedAliasNames(aliases) {
+		plan.Items = append(plan.Items, client.planSyncAlias(aliasName, aliases[aliasName], emojiAliasTakenPrefix, emojiAliasTakenSuffix, desiredNames))
+	}
+
+	if options.PruneOwnedPrefix != "" {
+		for _, name := range client.emojiNames() {
+			if _, isDesired := desiredNames[name]; !isDesired &&
+				strings.HasPrefix(name, options.PruneOwnedPrefix) {
+				plan.Items = append(plan.Items, PlanItem{
+					Action: PlanActionDelete,
+					Name:   name,
+					Reason: "owned emoji is not desired",
+				})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// planSyncAlias returns the sync plan item of a desired alias, recording its
+// remote name as desired.
+func (client *Client) planSyncAlias(aliasName, targetName, emojiAliasTakenPrefix, emojiAliasTakenSuffix string, desiredNames map[string]string) (item PlanItem) {
+	item = PlanItem{
+		Action:    PlanActionAdd,
+		AliasFor:  targetName,
+		Name:      aliasName,
+		TakenName: emojiAliasTakenPrefix + aliasName + emojiAliasTakenSuffix,
+	}
+
+	if _, isDesired := desiredNames[aliasName]; isDesired {
+		item.Action = PlanActionSkip
+		item.Reason = "name is already desired by " + desiredNames[aliasName]
+		item.TakenName = ""
+
+		return item
+	}
+
+	remoteEmoji, isExisting := client.emoji(item.Name)
+	if !isExisting {
+		remoteEmoji, isExisting = client.emoji(item.TakenName)
+		if isExisting {
+			item.Name = item.TakenName
+		}
+	}
+
+	desiredNames[item.Name] = AliasesFileName
+	if !isExisting {
+		return item
+	}
+
+	item.TakenName = ""
+	if remoteEmoji.IsAliasEmoji() &&
+		remoteEmoji.AliasFor == targetName {
+		item.Action = PlanActionSkip
+		item.Reason = "alias is up to date"
+	} else {
+		item.Action = PlanActionReplace
+		item.Reason = "remote emoji is not an alias for " + targetName
+	}
+
+	return item
+}
+
+// planSyncFile returns the sync plan item of a desired emoji file, recording
+// its remote names as desired even when the file is skipped as invalid, so
+// pruning keeps its remote emoji.
+func (client *Client) planSyncFile(ctx context.Context, path, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix string, imageConstraints *ImageConstraints, desiredNames map[string]string) (item PlanItem, err error) {
+	name, takenName := newEmojiNameFromFilePath(path, emojiAliasPrefix, emojiAliasSuffix, emojiAliasTakenPrefix, emojiAliasTakenSuffix)
+	item = PlanItem{
+		Action:    PlanActionAdd,
+		Name:      name,
+		Path:      path,
+		TakenName: takenName,
+	}
+
+	if desiredPath, isDesired := desiredNames[name]; isDesired {
+		item.Action = PlanActionSkip
+		item.Reason = "name is already desired by " + desiredPath
+		item.TakenName = ""
+
+		return item, nil
+	}
+
+	desiredNames[name] = path
+	desiredNames[takenName] = path
+
+	err = client.validateEmojiFile(path, imageConstraints)
+	if errors.Is(err, ErrorInvalidEmojiImage) {
+		item.Action = PlanActionSkip
+		item.Reason = err.Error()
+		item.TakenName = ""
+
+		return item, nil
+	} else if err != nil {
+		return item, errors.Wrapf(err, "validating emoji file failed, path: '%+v'", path)
+	}
+
+	remoteEmoji, isExisting := client.emoji(name)
+	if !isExisting {
+		remoteEmoji, isExisting = client.emoji(takenName)
+		if isExisting {
+			item.Name = takenName
+		}
+	}
+
+	if !isExisting {
+		return item, nil
+	}
+
+	item.TakenName = ""
+	if remoteEmoji.IsAliasEmoji() {
+		item.Action = PlanActionReplace
+		item.Reason = "remote emoji is an alias for " + remoteEmoji.AliasFor
+
+		return item, nil
+	}
+
+	isChanged, err := client.isEmojiChanged(ctx, remoteEmoji, path)
+	if err != nil {
+		return item, errors.Wrapf(err, "comparing emoji content failed, name: '%+v'", item.Name)
+	}
+
+	if isChanged {
+		item.Action = PlanActionReplace
+		item.Reason = "image content differs"
+	} else {
+		item.Action = PlanActionSkip
+		item.Reason = "image is up to date"
+	}
+
+	return item, nil
+}
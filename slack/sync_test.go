@@ -0,0 +1,94 @@
+package slack_test
+
+import (
+	"bytes"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+	"github.com/pregnor/slack-emoji-upload/slack/slacktest"
+)
+
+func TestApplyPlanReplaceKeepsAliases(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("party", newPNG(t, color.White))
+	server.AddAlias("party-alias", "party")
+	server.AddAlias("party-other", "party")
+	client := newTestClient(t, server)
+
+	directoryPath, removeDirectory := newTempDirectory(t)
+	defer removeDirectory()
+	writeFile(t, filepath.Join(directoryPath, "party.png"), newPNG(t, color.Black))
+
+	plan, err := client.PlanSync(directoryPath, "", "", "", "-2", slack.SyncOptions{})
+	if err != nil {
+		t.Fatalf("planning sync failed, error: '%+v'", err)
+	} else if len(plan.Items) != 1 ||
+		plan.Items[0].Action != slack.PlanActionReplace {
+		t.Fatalf("sync plan mismatches, expected a single replacement, actual: '%+v'", plan.Items)
+	}
+
+	err = client.ApplyPlan(plan, "", "-2")
+	if err != nil {
+		t.Fatalf("applying sync plan failed, error: '%+v'", err)
+	}
+
+	if image, _ := server.Image("party"); !bytes.Equal(image, newPNG(t, color.Black)) {
+		t.Errorf("emoji image is not replaced")
+	}
+
+	emojis := server.Emojis()
+	for _, aliasName := range []string{"party-alias", "party-other"} {
+		if emojis[aliasName].AliasFor != "party" {
+			t.Errorf("alias of replaced emoji is lost, name: '%+v', emojis: '%+v'", aliasName, emojis)
+		} else if client.Emojis[aliasName].AliasFor != "party" {
+			t.Errorf("alias of replaced emoji is unknown to the client, name: '%+v'", aliasName)
+		}
+	}
+}
+
+func TestPlanSyncPruneKeepsInvalidFiles(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	server.AddEmoji("own-big", newPNG(t, color.White))
+	server.AddEmoji("own-gone", newPNG(t, color.White))
+	client := newTestClient(t, server)
+
+	directoryPath, removeDirectory := newTempDirectory(t)
+	defer removeDirectory()
+	writeFile(t, filepath.Join(directoryPath, "own-big.png"), newPNG(t, color.Black))
+
+	plan, err := client.PlanSync(directoryPath, "", "", "", "-2", slack.SyncOptions{
+		ImageConstraints: &slack.ImageConstraints{MaxFileSize: 16},
+		PruneOwnedPrefix: "own-",
+	})
+	if err != nil {
+		t.Fatalf("planning sync failed, error: '%+v'", err)
+	}
+
+	for _, item := range plan.Items {
+		if item.Name == "own-big" &&
+			item.Action != slack.PlanActionSkip {
+			t.Errorf("invalid file is not skipped, item: '%+v'", item)
+		} else if item.Name == "own-gone" &&
+			item.Action != slack.PlanActionDelete {
+			t.Errorf("undesired owned emoji is not deleted, item: '%+v'", item)
+		}
+	}
+
+	err = client.ApplyPlan(plan, "", "-2")
+	if err != nil {
+		t.Fatalf("applying sync plan failed, error: '%+v'", err)
+	}
+
+	emojis := server.Emojis()
+	if _, isExisting := emojis["own-big"]; !isExisting {
+		t.Errorf("emoji of invalid file is pruned, emojis: '%+v'", emojis)
+	} else if _, isExisting := emojis["own-gone"]; isExisting {
+		t.Errorf("undesired owned emoji is not pruned, emojis: '%+v'", emojis)
+	}
+}
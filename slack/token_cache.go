@@ -0,0 +1,137 @@
+package slack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// TokenCacheTTL is the duration a discovered API token is reused for
+	// before it is discovered again. Slack does not publish the lifetime of
+	// the web client's tokens, an invalidated token is discovered again
+	// regardless.
+	TokenCacheTTL = 12 * time.Hour
+)
+
+// TokenCache persists the API tokens discovered from the session cookies,
+// so consecutive runs skip scraping the customize/emoji page. The entries are
+// keyed by the hash of the host and the cookie, the cookies themselves are
+// not stored.
+type TokenCache struct {
+	entries map[string]tokenCacheEntry
+	mutex   sync.Mutex
+	path    string
+}
+
+// tokenCacheEntry describes a cached API token.
+type tokenCacheEntry struct {
+	APIToken  string    `json:"api_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// OpenTokenCache loads the token cache file at the specified path, starting
+// empty when the file does not exist yet.
+func OpenTokenCache(cachePath string) (cache *TokenCache, err error) {
+	if cachePath == "" {
+		return nil, fmt.Errorf("token cache path is empty")
+	}
+
+	cache = &TokenCache{
+		entries: make(map[string]tokenCacheEntry),
+		path:    cachePath,
+	}
+
+	data, err := ioutil.ReadFile(cachePath)
+	if os.IsNotExist(err) {
+		return cache, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "reading token cache file failed, path: '%+v'", cachePath)
+	}
+
+	err = json.Unmarshal(data, &cache.entries)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling token cache failed, path: '%+v'", cachePath)
+	}
+
+	return cache, nil
+}
+
+// Save writes the unexpired cached API tokens to the cache file atomically,
+// only accessible by its owner.
+func (cache *TokenCache) Save() (err error) {
+	if cache == nil {
+		return nil
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	now := time.Now()
+	for key, entry := range cache.entries {
+		if !now.Before(entry.ExpiresAt) {
+			delete(cache.entries, key)
+		}
+	}
+
+	data, err := json.MarshalIndent(cache.entries, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "marshalling token cache failed, path: '%+v'", cache.path)
+	}
+
+	err = writeFileAtomically(cache.path, append(data, '\n'), 0600)
+	if err != nil {
+		return errors.Wrapf(err, "writing token cache file failed, path: '%+v'", cache.path)
+	}
+
+	return nil
+}
+
+// SetToken caches the API token discovered with the cookie on the host until
+// TokenCacheTTL elapses.
+func (cache *TokenCache) SetToken(host, cookie, apiToken string) {
+	if cache == nil {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.entries[tokenCacheKey(host, cookie)] = tokenCacheEntry{
+		APIToken:  apiToken,
+		ExpiresAt: time.Now().Add(TokenCacheTTL).UTC(),
+	}
+}
+
+// Token returns the unexpired cached API token discovered with the cookie on
+// the host.
+func (cache *TokenCache) Token(host, cookie string) (apiToken string, isExisting bool) {
+	if cache == nil {
+		return "", false
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, isExisting := cache.entries[tokenCacheKey(host, cookie)]
+	if !isExisting ||
+		!time.Now().Before(entry.ExpiresAt) {
+		return "", false
+	}
+
+	return entry.APIToken, true
+}
+
+// tokenCacheKey returns the cache key of the host and cookie pair.
+func tokenCacheKey(host, cookie string) (key string) {
+	hash := sha256.Sum256([]byte(host + "\n" + cookie))
+
+	return hex.EncodeToString(hash[:])
+}
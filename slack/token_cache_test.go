@@ -0,0 +1,176 @@
+package slack_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+	"github.com/pregnor/slack-emoji-upload/slack/slacktest"
+)
+
+func TestNewSlackClientWithOptionsTokenCache(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	directoryPath, removeDirectory := newTempDirectory(t)
+	defer removeDirectory()
+	cachePath := filepath.Join(directoryPath, "token-cache.json")
+
+	for index := 0; index < 2; index++ {
+		tokenCache, err := slack.OpenTokenCache(cachePath)
+		if err != nil {
+			t.Fatalf("opening token cache failed, error: '%+v'", err)
+		}
+
+		_, err = slack.NewSlackClientWithOptions("slacktest", "d=slacktest", slack.ClientOptions{
+			BaseURL:    server.URL(),
+			TokenCache: tokenCache,
+		})
+		if err != nil {
+			t.Fatalf("instantiating client failed, error: '%+v'", err)
+		}
+	}
+
+	if count := server.RequestCount(slacktest.CustomizeEmojiPath); count != 1 {
+		t.Errorf("API token discovery count mismatches, expected: '%+v', actual: '%+v'", 1, count)
+	}
+
+	writeTokenCache(t, cachePath, server.URL(), "d=slacktest", slacktest.DefaultAPIToken, time.Now().Add(-time.Minute))
+
+	tokenCache, err := slack.OpenTokenCache(cachePath)
+	if err != nil {
+		t.Fatalf("opening token cache failed, error: '%+v'", err)
+	}
+
+	_, err = slack.NewSlackClientWithOptions("slacktest", "d=slacktest", slack.ClientOptions{
+		BaseURL:    server.URL(),
+		TokenCache: tokenCache,
+	})
+	if err != nil {
+		t.Fatalf("instantiating client failed, error: '%+v'", err)
+	} else if count := server.RequestCount(slacktest.CustomizeEmojiPath); count != 2 {
+		t.Errorf("expired API token is not discovered again, discovery count: '%+v'", count)
+	}
+}
+
+func TestTokenCacheExpiry(t *testing.T) {
+	directoryPath, removeDirectory := newTempDirectory(t)
+	defer removeDirectory()
+	cachePath := filepath.Join(directoryPath, "token-cache.json")
+	writeTokenCache(t, cachePath, "https://slacktest.slack.com", "d=expired", "xoxs-expired", time.Now().Add(-time.Minute))
+
+	tokenCache, err := slack.OpenTokenCache(cachePath)
+	if err != nil {
+		t.Fatalf("opening token cache failed, error: '%+v'", err)
+	}
+
+	if apiToken, isExisting := tokenCache.Token("https://slacktest.slack.com", "d=expired"); isExisting {
+		t.Errorf("expired API token is returned, API token: '%+v'", apiToken)
+	}
+
+	tokenCache.SetToken("https://slacktest.slack.com", "d=fresh", "xoxs-fresh")
+	if apiToken, _ := tokenCache.Token("https://slacktest.slack.com", "d=fresh"); apiToken != "xoxs-fresh" {
+		t.Errorf("cached API token mismatches, expected: '%+v', actual: '%+v'", "xoxs-fresh", apiToken)
+	} else if _, isExisting := tokenCache.Token("https://other.slack.com", "d=fresh"); isExisting {
+		t.Errorf("API token is returned for another host")
+	}
+
+	err = tokenCache.Save()
+	if err != nil {
+		t.Fatalf("saving token cache failed, error: '%+v'", err)
+	}
+
+	entries := readTokenCache(t, cachePath)
+	if len(entries) != 1 {
+		t.Errorf("saved token cache entries mismatch, expected only the unexpired entry, actual: '%+v'", entries)
+	}
+
+	for _, entry := range entries {
+		if expiresAt := entry.ExpiresAt; expiresAt.Before(time.Now().Add(slack.TokenCacheTTL-time.Minute)) ||
+			expiresAt.After(time.Now().Add(slack.TokenCacheTTL)) {
+			t.Errorf("cached API token expiry mismatches the TTL, expires at: '%+v'", expiresAt)
+		}
+	}
+}
+
+func TestTokenCacheSave(t *testing.T) {
+	directoryPath, removeDirectory := newTempDirectory(t)
+	defer removeDirectory()
+	cachePath := filepath.Join(directoryPath, "token-cache.json")
+
+	tokenCache, err := slack.OpenTokenCache(cachePath)
+	if err != nil {
+		t.Fatalf("opening missing token cache failed, error: '%+v'", err)
+	}
+
+	tokenCache.SetToken("https://slacktest.slack.com", "d=secret-cookie", "xoxs-cached")
+	err = tokenCache.Save()
+	if err != nil {
+		t.Fatalf("saving token cache failed, error: '%+v'", err)
+	}
+
+	info, err := os.Stat(cachePath)
+	if err != nil {
+		t.Fatalf("checking token cache file failed, error: '%+v'", err)
+	} else if info.Mode().Perm() != 0600 {
+		t.Errorf("token cache file mode mismatches, expected: '%+v', actual: '%+v'", os.FileMode(0600), info.Mode().Perm())
+	}
+
+	data, _ := ioutil.ReadFile(cachePath)
+	if bytes.Contains(data, []byte("secret-cookie")) {
+		t.Errorf("token cache file stores the cookie, data: '%s'", data)
+	}
+
+	reopenedTokenCache, err := slack.OpenTokenCache(cachePath)
+	if err != nil {
+		t.Fatalf("reopening token cache failed, error: '%+v'", err)
+	} else if apiToken, _ := reopenedTokenCache.Token("https://slacktest.slack.com", "d=secret-cookie"); apiToken != "xoxs-cached" {
+		t.Errorf("reopened API token mismatches, expected: '%+v', actual: '%+v'", "xoxs-cached", apiToken)
+	}
+}
+
+// tokenCacheEntry describes the persisted form of a cached API token.
+type tokenCacheEntry struct {
+	APIToken  string    `json:"api_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// readTokenCache returns the entries of the token cache file.
+func readTokenCache(t *testing.T, cachePath string) (entries map[string]tokenCacheEntry) {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("reading token cache failed, error: '%+v'", err)
+	}
+
+	err = json.Unmarshal(data, &entries)
+	if err != nil {
+		t.Fatalf("unmarshalling token cache failed, error: '%+v'", err)
+	}
+
+	return entries
+}
+
+// writeTokenCache writes a token cache file of the single API token cached
+// for the host and cookie until the expiry.
+func writeTokenCache(t *testing.T, cachePath, host, cookie, apiToken string, expiresAt time.Time) {
+	t.Helper()
+
+	hash := sha256.Sum256([]byte(host + "\n" + cookie))
+	data, err := json.Marshal(map[string]tokenCacheEntry{
+		hex.EncodeToString(hash[:]): {APIToken: apiToken, ExpiresAt: expiresAt},
+	})
+	if err != nil {
+		t.Fatalf("marshalling token cache failed, error: '%+v'", err)
+	}
+
+	writeFile(t, cachePath, data)
+}
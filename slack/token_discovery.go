@@ -0,0 +1,185 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/html"
+	"gopkg.in/resty.v1"
+)
+
+const (
+	apiTokenRawRegex        = `"?api_token"?\s*:\s*"([^"]+)"`
+	bootDataRawRegex        = `boot_data\s*=\s*\{`
+	signInFormRawRegex      = `(?i)sign_?in|login`
+	tokenAssignmentRawRegex = `["']?\b(?:api_token|token)["']?\s*[:=]\s*["'](xox[a-z]-[^"'\s]+)["']`
+)
+
+var (
+	apiTokenRegex        = regexp.MustCompile(apiTokenRawRegex)
+	bootDataRegex        = regexp.MustCompile(bootDataRawRegex)
+	signInFormRegex      = regexp.MustCompile(signInFormRawRegex)
+	tokenAssignmentRegex = regexp.MustCompile(tokenAssignmentRawRegex)
+
+	// apiTokenStrategies are the ways of finding the API token in the
+	// customize/emoji page in the order they are tried.
+	apiTokenStrategies = []apiTokenStrategy{
+		apiTokenFromBootData,
+		apiTokenFromScripts,
+		apiTokenFromAssignments,
+	}
+)
+
+// apiTokenStrategy returns the API token found in the parsed customize/emoji
+// page or its raw body, an empty token when the strategy does not apply.
+type apiTokenStrategy func(document *html.Node, body string) (apiToken string)
+
+// apiTokenFromAssignments returns the Slack token assigned to an `api_token`
+// or `token` key or variable anywhere in the raw page.
+func apiTokenFromAssignments(document *html.Node, body string) (apiToken string) {
+	groups := tokenAssignmentRegex.FindStringSubmatch(body)
+	if len(groups) != 2 {
+		return ""
+	}
+
+	return groups[1]
+}
+
+// apiTokenFromBootData returns the `api_token` field of the page's boot data
+// JSON object.
+func apiTokenFromBootData(document *html.Node, body string) (apiToken string) {
+	for _, script := range scriptTexts(document) {
+		location := bootDataRegex.FindStringIndex(script)
+		if location == nil {
+			continue
+		}
+
+		bootData := struct {
+			APIToken string `json:"api_token"`
+		}{}
+		err := json.NewDecoder(strings.NewReader(script[location[1]-1:])).Decode(&bootData)
+		if err == nil &&
+			bootData.APIToken != "" {
+			return bootData.APIToken
+		}
+	}
+
+	return ""
+}
+
+// apiTokenFromScripts returns the first `api_token` field of any script of
+// the page regardless of its position and attributes.
+func apiTokenFromScripts(document *html.Node, body string) (apiToken string) {
+	for _, script := range scriptTexts(document) {
+		groups := apiTokenRegex.FindStringSubmatch(script)
+		if len(groups) == 2 {
+			return groups[1]
+		}
+	}
+
+	return ""
+}
+
+// htmlAttribute returns the value of the node's attribute of the specified
+// key.
+func htmlAttribute(node *html.Node, key string) (value string) {
+	for _, attribute := range node.Attr {
+		if strings.EqualFold(attribute.Key, key) {
+			return attribute.Val
+		}
+	}
+
+	return ""
+}
+
+// isSignInPage returns whether the document is a sign in page by its title,
+// password inputs or sign in form actions.
+func isSignInPage(document *html.Node) (isSignIn bool) {
+	if document.Type == html.ElementNode {
+		switch document.Data {
+		case "form":
+			if signInFormRegex.MatchString(htmlAttribute(document, "action")) {
+				return true
+			}
+		case "input":
+			if strings.EqualFold(htmlAttribute(document, "type"), "password") {
+				return true
+			}
+		case "title":
+			if document.FirstChild != nil &&
+				strings.Contains(strings.ToLower(document.FirstChild.Data), "sign in") {
+				return true
+			}
+		}
+	}
+
+	for child := document.FirstChild; child != nil; child = child.NextSibling {
+		if isSignInPage(child) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scriptTexts returns the texts of every script element of the document in
+// document order.
+func scriptTexts(document *html.Node) (texts []string) {
+	if document.Type == html.ElementNode &&
+		document.Data == "script" {
+		for child := document.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.TextNode {
+				texts = append(texts, child.Data)
+			}
+		}
+
+		return texts
+	}
+
+	for child := document.FirstChild; child != nil; child = child.NextSibling {
+		texts = append(texts, scriptTexts(child)...)
+	}
+
+	return texts
+}
+
+// apiTokenFromResponse returns the API token found in the customize/emoji
+// page response by the first applicable strategy, failing with
+// ErrorInvalidCookie when Slack redirected to or served its sign in page.
+func (client *Client) apiTokenFromResponse(response *resty.Response) (apiToken string, err error) {
+	if response.StatusCode() >= 300 &&
+		response.StatusCode() < 400 {
+		location := response.Header().Get("Location")
+		locationURL, err := url.Parse(location)
+		if err == nil &&
+			strings.TrimSuffix(locationURL.Path, "/") == "/"+strings.Trim(client.CustomizeEmojiPath, "/") {
+			return "", fmt.Errorf("customize/emoji page is redirected, set the base URL to the redirect location, location: '%s'", client.redact(location))
+		}
+
+		return "", errors.WithMessagef(ErrorInvalidCookie, "redirected to sign in, location: '%s'", client.redact(location))
+	}
+
+	body := string(response.Body())
+	document, err := html.Parse(bytes.NewReader(response.Body()))
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing API token body response failed, raw body: '%+v'", client.redact(body))
+	}
+
+	for _, strategy := range apiTokenStrategies {
+		apiToken = strategy(document, body)
+		if apiToken != "" {
+			return apiToken, nil
+		}
+	}
+
+	if isSignInPage(document) {
+		return "", errors.WithMessage(ErrorInvalidCookie, "sign in page served")
+	}
+
+	return "", fmt.Errorf("API token not found, raw document: '%+v'", client.redact(body))
+}
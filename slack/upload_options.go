@@ -0,0 +1,32 @@
+package slack
+
+// UploadOptions describes the optional behaviour of bulk emoji uploads.
+type UploadOptions struct {
+	// Concurrency is the number of emojis uploaded in parallel, values below
+	// 1 upload one emoji at a time.
+	Concurrency int
+
+	// DryRun writes the upload plan instead of uploading when set.
+	DryRun *DryRun
+
+	// ImageConstraints validates every emoji file before uploading it when
+	// set, skipping the invalid files with their reason instead of uploading.
+	ImageConstraints *ImageConstraints
+
+	// IsContinuingOnError records a failed emoji file and continues with the
+	// remaining files instead of aborting, failing only after the whole
+	// directory is processed.
+	IsContinuingOnError bool
+
+	// IsReplacingChanged compares the image of every existing emoji with its
+	// file and deletes and uploads the emoji again when they differ, instead
+	// of skipping it by its name.
+	IsReplacingChanged bool
+
+	// Journal records the outcome of every emoji file and, when opened for
+	// resuming, skips the files completed by an earlier run.
+	Journal *Journal
+
+	// Report collects the outcome of every emoji file when set.
+	Report *UploadReport
+}
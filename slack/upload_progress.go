@@ -0,0 +1,76 @@
+package slack
+
+import (
+	"fmt"
+	"sync"
+)
+
+// uploadProgress tracks the counters of a bulk upload shared by its workers.
+type uploadProgress struct {
+	failCount   int
+	mutex       sync.Mutex
+	skipCount   int
+	totalCount  int
+	uploadCount int
+}
+
+// fail counts a failed emoji and returns the resulting progress summary.
+func (progress *uploadProgress) fail() (summary string) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+
+	progress.failCount++
+
+	return progress.summary()
+}
+
+// skip counts a skipped emoji and returns the resulting progress summary.
+func (progress *uploadProgress) skip() (summary string) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+
+	progress.skipCount++
+
+	return progress.summary()
+}
+
+// upload counts an uploaded emoji and returns the resulting progress summary.
+func (progress *uploadProgress) upload() (summary string) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+
+	progress.uploadCount++
+
+	return progress.summary()
+}
+
+// summary returns the human readable summary of the counters, the caller is
+// responsible for locking.
+func (progress *uploadProgress) summary() (summary string) {
+	existingCount := progress.skipCount + progress.uploadCount
+	remainingCount := progress.totalCount - existingCount - progress.failCount
+
+	return fmt.Sprintf(
+		"Skipped+Uploaded=Existing: %d+%d=%d (%.2f%%+%.2f%%=%.2f%%), Failed: %d (%.2f%%), Remaining: %d (%.2f%%), total: %d",
+		progress.skipCount,
+		progress.uploadCount,
+		existingCount,
+		percentage(progress.skipCount, progress.totalCount),
+		percentage(progress.uploadCount, progress.totalCount),
+		percentage(existingCount, progress.totalCount),
+		progress.failCount,
+		percentage(progress.failCount, progress.totalCount),
+		remainingCount,
+		percentage(remainingCount, progress.totalCount),
+		progress.totalCount,
+	)
+}
+
+// percentage returns the part's ratio of the total in percents.
+func percentage(part, total int) (percentage float64) {
+	if total == 0 {
+		return 0.0
+	}
+
+	return float64(part) / float64(total) * 100.0
+}
@@ -0,0 +1,119 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// UploadReport collects the outcome of every emoji file of a bulk upload.
+type UploadReport struct {
+	Entries []JournalEntry `json:"entries"`
+	mutex   sync.Mutex
+}
+
+// Count returns the number of emoji files with the specified outcome.
+func (report *UploadReport) Count(outcome UploadOutcome) (count int) {
+	if report == nil {
+		return 0
+	}
+
+	report.mutex.Lock()
+	defer report.mutex.Unlock()
+
+	for _, entry := range report.Entries {
+		if entry.Outcome == outcome {
+			count++
+		}
+	}
+
+	return count
+}
+
+// String returns the human readable form of the report, a summary of the
+// outcomes followed by the failed and invalid files with their errors.
+func (report *UploadReport) String() (text string) {
+	if report == nil {
+		return ""
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf(
+		"Report: %d uploaded, %d renamed to taken name, %d replaced, %d skipped, %d invalid, %d failed.\n",
+		report.Count(UploadOutcomeUploaded),
+		report.Count(UploadOutcomeNameTaken),
+		report.Count(UploadOutcomeReplaced),
+		report.Count(UploadOutcomeSkipped),
+		report.Count(UploadOutcomeInvalid),
+		report.Count(UploadOutcomeFailed),
+	))
+
+	for _, entry := range report.sortedEntries() {
+		switch entry.Outcome {
+		case UploadOutcomeFailed:
+			builder.WriteString("  failed " + entry.Path + ": " + entry.Error + "\n")
+		case UploadOutcomeInvalid:
+			builder.WriteString("  invalid " + entry.Path + ": " + entry.Error + "\n")
+		case UploadOutcomeNameTaken:
+			builder.WriteString("  renamed " + entry.Path + " to " + entry.Name + "\n")
+		}
+	}
+
+	return builder.String()
+}
+
+// WriteFile writes the report as an indented JSON document with its entries
+// ordered by path.
+func (report *UploadReport) WriteFile(reportPath string) (err error) {
+	if report == nil {
+		return fmt.Errorf("report is nil")
+	}
+
+	data, err := json.MarshalIndent(
+		UploadReport{
+			Entries: report.sortedEntries(),
+		},
+		"",
+		"  ",
+	)
+	if err != nil {
+		return errors.Wrapf(err, "marshalling report failed, path: '%+v'", reportPath)
+	}
+
+	err = writeFileAtomically(reportPath, append(data, '\n'), 0644)
+	if err != nil {
+		return errors.Wrapf(err, "writing report file failed, path: '%+v'", reportPath)
+	}
+
+	return nil
+}
+
+// record appends the entry to the report.
+func (report *UploadReport) record(entry JournalEntry) {
+	if report == nil {
+		return
+	}
+
+	report.mutex.Lock()
+	defer report.mutex.Unlock()
+
+	report.Entries = append(report.Entries, entry)
+}
+
+// sortedEntries returns a copy of the report's entries ordered by path.
+func (report *UploadReport) sortedEntries() (entries []JournalEntry) {
+	report.mutex.Lock()
+	defer report.mutex.Unlock()
+
+	entries = make([]JournalEntry, len(report.Entries))
+	copy(entries, report.Entries)
+	sort.Slice(entries, func(firstIndex, secondIndex int) bool {
+		return entries[firstIndex].Path < entries[secondIndex].Path
+	})
+
+	return entries
+}
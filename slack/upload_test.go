@@ -0,0 +1,242 @@
+package slack_test
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/pregnor/slack-emoji-upload/slack"
+	"github.com/pregnor/slack-emoji-upload/slack/slacktest"
+)
+
+func TestPostEmojisWithOptionsCancelled(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	client := newTestClient(t, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uploadCount := 0
+	uploadMutex := sync.Mutex{}
+	client.RequestHooks = &slack.RequestHooks{
+		AfterResponse: func(attempt slack.RequestAttempt) {
+			if attempt.Error != nil ||
+				!strings.HasSuffix(attempt.URL, slacktest.EmojiAddPath) {
+				return
+			}
+
+			uploadMutex.Lock()
+			defer uploadMutex.Unlock()
+
+			uploadCount++
+			if uploadCount == 3 {
+				cancel()
+			}
+		},
+	}
+
+	directoryPath, removeDirectory := newTempDirectory(t)
+	defer removeDirectory()
+	paths := writeEmojiFiles(t, directoryPath, 20)
+
+	journalDirectoryPath, removeJournalDirectory := newTempDirectory(t)
+	defer removeJournalDirectory()
+	journalPath := filepath.Join(journalDirectoryPath, "journal.jsonl")
+	journal, err := slack.OpenJournal(journalPath, false)
+	if err != nil {
+		t.Fatalf("opening journal failed, error: '%+v'", err)
+	}
+
+	report := &slack.UploadReport{}
+	err = client.PostEmojisWithOptionsContext(ctx, directoryPath, "", "", "", "-2", slack.UploadOptions{
+		Concurrency: 4,
+		Journal:     journal,
+		Report:      report,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("posting emojis returned unexpected error, expected: '%+v', actual: '%+v'", context.Canceled, err)
+	}
+
+	err = journal.Close()
+	if err != nil {
+		t.Fatalf("closing journal failed, error: '%+v'", err)
+	}
+
+	loadedJournal, err := slack.LoadJournal(journalPath)
+	if err != nil {
+		t.Fatalf("loading journal failed, error: '%+v'", err)
+	}
+	defer func() { _ = loadedJournal.Close() }()
+
+	emojis := server.Emojis()
+	if len(emojis) >= len(paths) {
+		t.Errorf("uploading is not cancelled, emoji count: '%+v'", len(emojis))
+	}
+
+	completedCount := 0
+	for _, path := range paths {
+		if !loadedJournal.IsCompleted(path) {
+			continue
+		}
+
+		completedCount++
+		entry, _ := loadedJournal.Entry(path)
+		if _, isExisting := emojis[entry.Name]; !isExisting {
+			t.Errorf("journaled emoji is not uploaded, entry: '%+v'", entry)
+		}
+	}
+
+	if completedCount < 3 {
+		t.Errorf("uploads before the cancellation are not journaled, completed count: '%+v'", completedCount)
+	} else if count := report.Count(slack.UploadOutcomeUploaded); count != completedCount {
+		t.Errorf("reported upload count mismatches, expected: '%+v', actual: '%+v'", completedCount, count)
+	}
+}
+
+func TestPostEmojisWithOptionsConcurrency(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	client := newTestClient(t, server)
+
+	directoryPath, removeDirectory := newTempDirectory(t)
+	defer removeDirectory()
+	paths := writeEmojiFiles(t, directoryPath, 20)
+
+	journalDirectoryPath, removeJournalDirectory := newTempDirectory(t)
+	defer removeJournalDirectory()
+	journalPath := filepath.Join(journalDirectoryPath, "journal.jsonl")
+	journal, err := slack.OpenJournal(journalPath, false)
+	if err != nil {
+		t.Fatalf("opening journal failed, error: '%+v'", err)
+	}
+
+	report := &slack.UploadReport{}
+	err = client.PostEmojisWithOptions(directoryPath, "", "", "", "-2", slack.UploadOptions{
+		Concurrency: 4,
+		Journal:     journal,
+		Report:      report,
+	})
+	if err != nil {
+		t.Fatalf("posting emojis failed, error: '%+v'", err)
+	}
+
+	err = journal.Close()
+	if err != nil {
+		t.Fatalf("closing journal failed, error: '%+v'", err)
+	}
+
+	loadedJournal, err := slack.LoadJournal(journalPath)
+	if err != nil {
+		t.Fatalf("loading journal failed, error: '%+v'", err)
+	}
+	defer func() { _ = loadedJournal.Close() }()
+
+	emojis := server.Emojis()
+	for _, path := range paths {
+		entry, isExisting := loadedJournal.Entry(path)
+		if !isExisting ||
+			entry.Outcome != slack.UploadOutcomeUploaded {
+			t.Errorf("journal entry mismatches, path: '%+v', entry: '%+v'", path, entry)
+		} else if _, isExisting := emojis[entry.Name]; !isExisting {
+			t.Errorf("emoji is not uploaded, name: '%+v'", entry.Name)
+		}
+	}
+
+	if count := report.Count(slack.UploadOutcomeUploaded); count != len(paths) {
+		t.Errorf("reported upload count mismatches, expected: '%+v', actual: '%+v'", len(paths), count)
+	} else if count := server.RequestCount(slacktest.EmojiAddPath); count != len(paths) {
+		t.Errorf("request count mismatches, expected: '%+v', actual: '%+v'", len(paths), count)
+	}
+}
+
+func TestPostEmojisWithOptionsContinuingOnError(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	server.InjectFaults(slacktest.EmojiAddPath, slacktest.Fault{SlackError: "invalid_name"})
+
+	directoryPath, removeDirectory := newTempDirectory(t)
+	defer removeDirectory()
+	paths := writeEmojiFiles(t, directoryPath, 6)
+
+	report := &slack.UploadReport{}
+	err := client.PostEmojisWithOptions(directoryPath, "", "", "", "-2", slack.UploadOptions{
+		Concurrency:         3,
+		IsContinuingOnError: true,
+		Report:              report,
+	})
+	if err == nil ||
+		!strings.Contains(err.Error(), "failed count: '1'") {
+		t.Errorf("posting emojis returned unexpected error, expected: '%+v', actual: '%+v'", "failed count: '1'", err)
+	}
+
+	if count := report.Count(slack.UploadOutcomeFailed); count != 1 {
+		t.Errorf("reported failure count mismatches, expected: '%+v', actual: '%+v'", 1, count)
+	} else if count := report.Count(slack.UploadOutcomeUploaded); count != len(paths)-1 {
+		t.Errorf("reported upload count mismatches, expected: '%+v', actual: '%+v'", len(paths)-1, count)
+	} else if emojis := server.Emojis(); len(emojis) != len(paths)-1 {
+		t.Errorf("emojis after the failure are not uploaded, emojis: '%+v'", emojis)
+	}
+
+	for _, entry := range report.Entries {
+		if entry.Outcome == slack.UploadOutcomeFailed &&
+			!strings.Contains(entry.Error, "invalid_name") {
+			t.Errorf("failed entry error mismatches, entry: '%+v'", entry)
+		}
+	}
+
+	if text := report.String(); !strings.Contains(text, "1 failed") {
+		t.Errorf("report text is missing the failure, text: '%s'", text)
+	}
+}
+
+func TestPostEmojisWithOptionsStoppingOnError(t *testing.T) {
+	server := slacktest.NewServer()
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	server.InjectFaults(slacktest.EmojiAddPath, slacktest.Fault{SlackError: "invalid_name"})
+
+	directoryPath, removeDirectory := newTempDirectory(t)
+	defer removeDirectory()
+	paths := writeEmojiFiles(t, directoryPath, 6)
+
+	report := &slack.UploadReport{}
+	err := client.PostEmojisWithOptions(directoryPath, "", "", "", "-2", slack.UploadOptions{
+		Report: report,
+	})
+	if !errors.Is(err, slack.ErrorInvalidName) {
+		t.Errorf("posting emojis returned unexpected error, expected: '%+v', actual: '%+v'", slack.ErrorInvalidName, err)
+	}
+
+	if count := report.Count(slack.UploadOutcomeFailed); count != 1 {
+		t.Errorf("reported failure count mismatches, expected: '%+v', actual: '%+v'", 1, count)
+	} else if count := len(report.Entries); count != 1 {
+		t.Errorf("emojis after the failure are processed, entry count: '%+v', path count: '%+v'", count, len(paths))
+	}
+}
+
+// writeEmojiFiles writes the specified number of emoji files into the
+// directory and returns their paths.
+func writeEmojiFiles(t *testing.T, directoryPath string, count int) (paths []string) {
+	t.Helper()
+
+	paths = make([]string, 0, count)
+	for index := 0; index < count; index++ {
+		path := filepath.Join(directoryPath, fmt.Sprintf("emoji-%02d.png", index))
+		writeFile(t, path, newPNG(t, color.Gray{Y: uint8(index)}))
+		paths = append(paths, path)
+	}
+
+	return paths
+}
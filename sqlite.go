@@ -0,0 +1,423 @@
+package upload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	sqliteHeader            = "SQLite format 3\x00"
+	sqliteHeaderSize        = 100
+	sqliteInteriorTablePage = 0x05
+	sqliteLeafTablePage     = 0x0d
+	sqliteMaximumPageSize   = 65536
+	sqliteMaximumTreeDepth  = 64
+	sqliteMinimumPageSize   = 512
+	sqliteMinimumUsableSize = 480
+	sqliteWALFrameSize      = 24
+	sqliteWALHeaderSize     = 32
+)
+
+// sqliteDatabase is a minimal read-only SQLite 3 database scanning the rows of
+// its tables, enough to read the cookie databases of browsers without a cgo
+// or third party driver. The committed frames of the write-ahead log next to
+// the database are applied, as browsers keep recent changes there while they
+// are running.
+type sqliteDatabase struct {
+	data       []byte
+	pageSize   int
+	usableSize int
+	walPages   map[uint32][]byte
+}
+
+// openSQLiteDatabase reads the SQLite database file at the specified path
+// together with its write-ahead log if there is one.
+func openSQLiteDatabase(path string) (database *sqliteDatabase, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading SQLite database failed, path: '%+v'", path)
+	} else if len(data) < sqliteHeaderSize ||
+		string(data[:len(sqliteHeader)]) != sqliteHeader {
+		return nil, fmt.Errorf("file is not an SQLite database, path: '%+v'", path)
+	}
+
+	pageSize := int(binary.BigEndian.Uint16(data[16:18]))
+	if pageSize == 1 {
+		pageSize = sqliteMaximumPageSize
+	}
+
+	if pageSize < sqliteMinimumPageSize ||
+		pageSize > sqliteMaximumPageSize ||
+		pageSize&(pageSize-1) != 0 {
+		return nil, fmt.Errorf("SQLite database has an invalid page size, path: '%+v', page size: '%+v'", path, pageSize)
+	} else if pageSize-int(data[20]) < sqliteMinimumUsableSize {
+		return nil, fmt.Errorf("SQLite database reserves too many bytes per page, path: '%+v', reserved size: '%+v'", path, data[20])
+	}
+
+	database = &sqliteDatabase{
+		data:       data,
+		pageSize:   pageSize,
+		usableSize: pageSize - int(data[20]),
+		walPages:   make(map[uint32][]byte),
+	}
+
+	walData, err := ioutil.ReadFile(path + "-wal")
+	if os.IsNotExist(err) {
+		return database, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "reading SQLite write-ahead log failed, path: '%+v'", path+"-wal")
+	}
+
+	database.applyWAL(walData)
+
+	return database, nil
+}
+
+// applyWAL records the pages of the committed transactions of the write-ahead
+// log, ignoring the frames of an earlier log generation and the frames after
+// the last commit.
+func (database *sqliteDatabase) applyWAL(walData []byte) {
+	if len(walData) < sqliteWALHeaderSize ||
+		binary.BigEndian.Uint32(walData[0:4])&^1 != 0x377f0682 ||
+		int(binary.BigEndian.Uint32(walData[8:12])) != database.pageSize {
+		return
+	}
+
+	salts := walData[16:24]
+	pendingPages := make(map[uint32][]byte)
+	for offset := sqliteWALHeaderSize; offset+sqliteWALFrameSize+database.pageSize <= len(walData); offset += sqliteWALFrameSize + database.pageSize {
+		frameHeader := walData[offset : offset+sqliteWALFrameSize]
+		if !bytes.Equal(frameHeader[8:16], salts) {
+			return
+		}
+
+		pageStart := offset + sqliteWALFrameSize
+		pendingPages[binary.BigEndian.Uint32(frameHeader[0:4])] = walData[pageStart : pageStart+database.pageSize]
+
+		if binary.BigEndian.Uint32(frameHeader[4:8]) != 0 {
+			for pageNumber, page := range pendingPages {
+				database.walPages[pageNumber] = page
+			}
+			pendingPages = make(map[uint32][]byte)
+		}
+	}
+}
+
+// page returns the content of the specified page, preferring its committed
+// version in the write-ahead log.
+func (database *sqliteDatabase) page(pageNumber uint32) (page []byte, err error) {
+	if page, isExisting := database.walPages[pageNumber]; isExisting {
+		return page, nil
+	}
+
+	if pageNumber == 0 ||
+		int64(pageNumber) > int64(len(database.data)/database.pageSize) {
+		return nil, fmt.Errorf("SQLite page is out of range, page number: '%+v'", pageNumber)
+	}
+
+	start := (int(pageNumber) - 1) * database.pageSize
+
+	return database.data[start : start+database.pageSize], nil
+}
+
+// pageCount returns the number of pages of the database file and the
+// write-ahead log, bounding the size of any payload.
+func (database *sqliteDatabase) pageCount() (count int) {
+	return len(database.data)/database.pageSize + len(database.walPages)
+}
+
+// payload returns the payload of the cell of the specified total size whose
+// local part starts at the offset of the page, following its overflow pages.
+func (database *sqliteDatabase) payload(page []byte, offset int, size int) (payload []byte, err error) {
+	maximumLocalSize := database.usableSize - 35
+	localSize := size
+	if size > maximumLocalSize {
+		minimumLocalSize := (database.usableSize-12)*32/255 - 23
+		localSize = minimumLocalSize + (size-minimumLocalSize)%(database.usableSize-4)
+		if localSize > maximumLocalSize {
+			localSize = minimumLocalSize
+		}
+	}
+
+	if offset+localSize > len(page) {
+		return nil, fmt.Errorf("SQLite cell exceeds its page, offset: '%+v'", offset)
+	}
+
+	payload = append(make([]byte, 0, size), page[offset:offset+localSize]...)
+	if localSize == size {
+		return payload, nil
+	} else if offset+localSize+4 > len(page) {
+		return nil, fmt.Errorf("SQLite overflow pointer exceeds its page, offset: '%+v'", offset)
+	}
+
+	overflowPageNumber := binary.BigEndian.Uint32(page[offset+localSize:])
+	for len(payload) < size {
+		overflowPage, err := database.page(overflowPageNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		chunkSize := size - len(payload)
+		if chunkSize > database.usableSize-4 {
+			chunkSize = database.usableSize - 4
+		}
+
+		payload = append(payload, overflowPage[4:4+chunkSize]...)
+		overflowPageNumber = binary.BigEndian.Uint32(overflowPage[0:4])
+	}
+
+	return payload, nil
+}
+
+// scanTable visits the records of the table b-tree rooted at the specified
+// page in rowid order.
+func (database *sqliteDatabase) scanTable(pageNumber uint32, depth int, visit func(record []interface{}) (err error)) (err error) {
+	if depth > sqliteMaximumTreeDepth {
+		return fmt.Errorf("SQLite b-tree is too deep, page number: '%+v'", pageNumber)
+	}
+
+	page, err := database.page(pageNumber)
+	if err != nil {
+		return err
+	}
+
+	headerOffset := 0
+	if pageNumber == 1 {
+		headerOffset = sqliteHeaderSize
+	}
+
+	pageType := page[headerOffset]
+	cellCount := int(binary.BigEndian.Uint16(page[headerOffset+3:]))
+	cellPointerOffset := headerOffset + 8
+	if pageType == sqliteInteriorTablePage {
+		cellPointerOffset = headerOffset + 12
+	} else if pageType != sqliteLeafTablePage {
+		return fmt.Errorf("SQLite page is not a table page, page number: '%+v', type: '%+v'", pageNumber, pageType)
+	}
+
+	if cellPointerOffset+2*cellCount > len(page) {
+		return fmt.Errorf("SQLite cell pointers exceed their page, page number: '%+v'", pageNumber)
+	}
+
+	for cellIndex := 0; cellIndex < cellCount; cellIndex++ {
+		cellOffset := int(binary.BigEndian.Uint16(page[cellPointerOffset+2*cellIndex:]))
+		if cellOffset+4 > len(page) {
+			return fmt.Errorf("SQLite cell exceeds its page, page number: '%+v'", pageNumber)
+		}
+
+		if pageType == sqliteInteriorTablePage {
+			err = database.scanTable(binary.BigEndian.Uint32(page[cellOffset:]), depth+1, visit)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		payloadSize, length := sqliteVarint(page[cellOffset:])
+		cellOffset += length
+		_, length = sqliteVarint(page[cellOffset:])
+		cellOffset += length
+
+		if payloadSize > uint64(database.pageCount()*database.usableSize) {
+			return fmt.Errorf("SQLite cell payload exceeds the database, page number: '%+v', payload size: '%+v'", pageNumber, payloadSize)
+		}
+
+		payload, err := database.payload(page, cellOffset, int(payloadSize))
+		if err != nil {
+			return errors.Wrapf(err, "reading SQLite cell failed, page number: '%+v'", pageNumber)
+		}
+
+		record, err := sqliteRecord(payload)
+		if err != nil {
+			return errors.Wrapf(err, "decoding SQLite record failed, page number: '%+v'", pageNumber)
+		}
+
+		err = visit(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	if pageType == sqliteInteriorTablePage {
+		return database.scanTable(binary.BigEndian.Uint32(page[headerOffset+8:]), depth+1, visit)
+	}
+
+	return nil
+}
+
+// tableRows returns the rows of the named table as maps of column names to
+// values, which are nil, int64, float64, []byte or string.
+func (database *sqliteDatabase) tableRows(tableName string) (rows []map[string]interface{}, err error) {
+	rootPage, columns := uint32(0), []string(nil)
+	err = database.scanTable(1, 0, func(record []interface{}) (err error) {
+		if len(record) < 5 ||
+			record[0] != "table" ||
+			!strings.EqualFold(fmt.Sprint(record[1]), tableName) {
+			return nil
+		}
+
+		pageNumber, isInteger := record[3].(int64)
+		sql, isText := record[4].(string)
+		if !isInteger ||
+			!isText {
+			return fmt.Errorf("malformed schema record, table: '%+v'", tableName)
+		}
+
+		rootPage, columns = uint32(pageNumber), sqliteColumnNames(sql)
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "reading SQLite schema failed")
+	} else if rootPage == 0 {
+		return nil, fmt.Errorf("SQLite table does not exist, table: '%+v'", tableName)
+	}
+
+	err = database.scanTable(rootPage, 0, func(record []interface{}) (err error) {
+		row := make(map[string]interface{}, len(columns))
+		for index, column := range columns {
+			if index < len(record) {
+				row[column] = record[index]
+			}
+		}
+
+		rows = append(rows, row)
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading SQLite table failed, table: '%+v'", tableName)
+	}
+
+	return rows, nil
+}
+
+// sqliteColumnNames returns the column names declared by the CREATE TABLE
+// statement in their order, skipping the table constraints.
+func sqliteColumnNames(sql string) (columns []string) {
+	start, end := strings.Index(sql, "("), strings.LastIndex(sql, ")")
+	if start == -1 ||
+		end <= start {
+		return nil
+	}
+
+	definitions := make([]string, 0)
+	depth, definitionStart := 0, start+1
+	for index := start + 1; index < end; index++ {
+		switch sql[index] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				definitions = append(definitions, sql[definitionStart:index])
+				definitionStart = index + 1
+			}
+		}
+	}
+	definitions = append(definitions, sql[definitionStart:end])
+
+	for _, definition := range definitions {
+		fields := strings.Fields(definition)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "CHECK", "CONSTRAINT", "FOREIGN", "PRIMARY", "UNIQUE":
+			continue
+		}
+
+		columns = append(columns, strings.Trim(fields[0], "\"`[]'"))
+	}
+
+	return columns
+}
+
+// sqliteRecord decodes the values of a record payload.
+func sqliteRecord(payload []byte) (record []interface{}, err error) {
+	headerSize, length := sqliteVarint(payload)
+	if int(headerSize) < length ||
+		int(headerSize) > len(payload) {
+		return nil, fmt.Errorf("SQLite record header exceeds its payload, header size: '%+v'", headerSize)
+	}
+
+	header, body := payload[length:headerSize], payload[headerSize:]
+	for len(header) != 0 {
+		serialType, length := sqliteVarint(header)
+		header = header[length:]
+
+		size := 0
+		switch {
+		case serialType >= 12 &&
+			(serialType-12)/2 > uint64(len(body)):
+			return nil, fmt.Errorf("SQLite record value exceeds its payload, serial type: '%+v'", serialType)
+		case serialType >= 12:
+			size = int(serialType-12) / 2
+		case serialType >= 1 && serialType <= 4:
+			size = int(serialType)
+		case serialType == 5:
+			size = 6
+		case serialType == 6 || serialType == 7:
+			size = 8
+		}
+
+		if size > len(body) {
+			return nil, fmt.Errorf("SQLite record value exceeds its payload, serial type: '%+v'", serialType)
+		}
+
+		value := body[:size]
+		body = body[size:]
+
+		switch {
+		case serialType == 0:
+			record = append(record, nil)
+		case serialType >= 1 && serialType <= 6:
+			integer := int64(int8(value[0]))
+			for _, valueByte := range value[1:] {
+				integer = integer<<8 | int64(valueByte)
+			}
+			record = append(record, integer)
+		case serialType == 7:
+			record = append(record, math.Float64frombits(binary.BigEndian.Uint64(value)))
+		case serialType == 8 || serialType == 9:
+			record = append(record, int64(serialType-8))
+		case serialType >= 12 && serialType%2 == 0:
+			record = append(record, append([]byte(nil), value...))
+		case serialType >= 13:
+			record = append(record, string(value))
+		default:
+			return nil, fmt.Errorf("unsupported SQLite serial type, serial type: '%+v'", serialType)
+		}
+	}
+
+	return record, nil
+}
+
+// sqliteVarint decodes the big-endian variable length integer at the start of
+// the data, returning its value and length.
+func sqliteVarint(data []byte) (value uint64, length int) {
+	for length < len(data) &&
+		length < 9 {
+		if length == 8 {
+			return value<<8 | uint64(data[length]), length + 1
+		}
+
+		value = value<<7 | uint64(data[length]&0x7f)
+		length++
+		if data[length-1]&0x80 == 0 {
+			return value, length
+		}
+	}
+
+	return value, length
+}
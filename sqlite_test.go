@@ -0,0 +1,183 @@
+package upload
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenSQLiteDatabaseInvalidHeader(t *testing.T) {
+	testCases := []struct {
+		caseDescription string
+		modify          func(data []byte)
+	}{
+		{
+			caseDescription: "invalid magic",
+			modify:          func(data []byte) { copy(data, "SQLite format 2") },
+		},
+		{
+			caseDescription: "non power of two page size",
+			modify:          func(data []byte) { binary.BigEndian.PutUint16(data[16:18], 1000) },
+		},
+		{
+			caseDescription: "too many reserved bytes",
+			modify:          func(data []byte) { data[20] = 64 },
+		},
+		{
+			caseDescription: "too small page size",
+			modify:          func(data []byte) { binary.BigEndian.PutUint16(data[16:18], 256) },
+		},
+		{
+			caseDescription: "zero page size",
+			modify:          func(data []byte) { binary.BigEndian.PutUint16(data[16:18], 0) },
+		},
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "chromium", "Network", "Cookies"))
+	if err != nil {
+		t.Fatalf("reading fixture failed, error: '%+v'", err)
+	}
+
+	directoryPath, err := ioutil.TempDir("", "sqlite-test")
+	if err != nil {
+		t.Fatalf("creating temporary directory failed, error: '%+v'", err)
+	}
+	defer func() { _ = os.RemoveAll(directoryPath) }()
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			modifiedData := append([]byte(nil), data...)
+			testCase.modify(modifiedData)
+
+			path := filepath.Join(directoryPath, "Cookies")
+			err := ioutil.WriteFile(path, modifiedData, 0644)
+			if err != nil {
+				t.Fatalf("writing database failed, error: '%+v'", err)
+			}
+
+			_, err = openSQLiteDatabase(path)
+			if err == nil {
+				t.Errorf("opening invalid database succeeded")
+			}
+		})
+	}
+}
+
+func TestOpenSQLiteDatabaseMaximumPageSize(t *testing.T) {
+	data := make([]byte, 65536)
+	copy(data, sqliteHeader)
+	binary.BigEndian.PutUint16(data[16:18], 1)
+
+	directoryPath, err := ioutil.TempDir("", "sqlite-test")
+	if err != nil {
+		t.Fatalf("creating temporary directory failed, error: '%+v'", err)
+	}
+	defer func() { _ = os.RemoveAll(directoryPath) }()
+
+	path := filepath.Join(directoryPath, "Cookies")
+	err = ioutil.WriteFile(path, data, 0644)
+	if err != nil {
+		t.Fatalf("writing database failed, error: '%+v'", err)
+	}
+
+	database, err := openSQLiteDatabase(path)
+	if err != nil {
+		t.Fatalf("opening database failed, error: '%+v'", err)
+	} else if database.pageSize != 65536 {
+		t.Errorf("page size mismatches, expected: '%+v', actual: '%+v'", 65536, database.pageSize)
+	}
+}
+
+func TestSQLiteDatabaseScanTableCorruptCell(t *testing.T) {
+	testCases := []struct {
+		caseDescription string
+		cell            []byte
+	}{
+		{
+			caseDescription: "huge payload size",
+			cell:            []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+		},
+		{
+			caseDescription: "huge serial type",
+			cell:            []byte{0x0b, 0x01, 0x0a, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00},
+		},
+		{
+			caseDescription: "payload exceeding page",
+			cell:            []byte{0x81, 0x00, 0x01, 0x02, 0x01},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			page := make([]byte, 512)
+			page[0] = sqliteLeafTablePage
+			binary.BigEndian.PutUint16(page[3:5], 1)
+			binary.BigEndian.PutUint16(page[8:10], uint16(len(page)-len(testCase.cell)))
+			copy(page[len(page)-len(testCase.cell):], testCase.cell)
+
+			database := &sqliteDatabase{
+				data:       append(make([]byte, 512), page...),
+				pageSize:   512,
+				usableSize: 512,
+				walPages:   make(map[uint32][]byte),
+			}
+
+			err := database.scanTable(2, 0, func(record []interface{}) (err error) { return nil })
+			if err == nil {
+				t.Errorf("scanning corrupt table succeeded")
+			}
+		})
+	}
+}
+
+func TestSQLiteRecord(t *testing.T) {
+	testCases := []struct {
+		caseDescription string
+		expectedRecord  []interface{}
+		isValid         bool
+		payload         []byte
+	}{
+		{
+			caseDescription: "huge header size",
+			payload:         []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			caseDescription: "huge serial type",
+			payload:         []byte{0x0a, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			caseDescription: "reserved serial type",
+			payload:         []byte{0x02, 0x0a},
+		},
+		{
+			caseDescription: "value exceeding payload",
+			payload:         []byte{0x02, 0x06, 0x01},
+		},
+		{
+			caseDescription: "valid record",
+			expectedRecord:  []interface{}{nil, int64(-2), int64(1), "d", []byte{0x01}},
+			isValid:         true,
+			payload:         []byte{0x06, 0x00, 0x01, 0x09, 0x0f, 0x0e, 0xfe, 'd', 0x01},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.caseDescription, func(t *testing.T) {
+			actualRecord, err := sqliteRecord(testCase.payload)
+			if (err == nil) != testCase.isValid {
+				t.Fatalf("record validity mismatches, expected: '%+v', error: '%+v'", testCase.isValid, err)
+			} else if fmt.Sprintf("%#v", actualRecord) != fmt.Sprintf("%#v", testCase.expectedRecord) {
+				t.Errorf("record mismatches, expected: '%#v', actual: '%#v'", testCase.expectedRecord, actualRecord)
+			}
+		})
+	}
+}